@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/http"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
 	"github.com/joyent/freebsd-vpc/db"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -17,6 +18,10 @@ type Agent struct {
 
 	rpcListener net.Listener
 	rpcServer   *http.Server
+
+	mgmt        *mgmt.Mgmt
+	watchCancel context.CancelFunc
+	watchDone   chan struct{}
 }
 
 func New(config Config) (agent *Agent, err error) {
@@ -38,6 +43,7 @@ func New(config Config) (agent *Agent, err error) {
 	}
 
 	return &Agent{
+		config:      config,
 		dbPool:      dbPool,
 		rpcListener: rpcListener,
 		rpcServer:   rpcServer,
@@ -51,10 +57,59 @@ func (a *Agent) Start() error {
 
 	go a.rpcServer.Serve(a.rpcListener)
 
+	if err := a.startWatch(); err != nil {
+		return errors.Wrap(err, "unable to watch VPC objects")
+	}
+
+	return nil
+}
+
+// startWatch follows the lifecycle of the VPC objects in the kernel until
+// Shutdown is called.
+func (a *Agent) startWatch() error {
+	m, err := mgmt.New(nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to open VPC Management handle")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := m.Watch(ctx, a.config.AgentConfig.WatchInterval)
+	if err != nil {
+		cancel()
+		m.Close()
+		return errors.Wrap(err, "unable to start VPC object watch")
+	}
+
+	a.mgmt = m
+	a.watchCancel = cancel
+	a.watchDone = make(chan struct{})
+
+	go func() {
+		defer close(a.watchDone)
+
+		for ev := range events {
+			switch ev.Type {
+			case mgmt.EventError:
+				log.Warn().Object("watch", ev).Msg("unable to poll VPC objects")
+			default:
+				log.Info().Object("watch", ev).Msg("VPC object changed")
+			}
+		}
+	}()
+
 	return nil
 }
 
 func (a *Agent) Shutdown() error {
+	if a.watchCancel != nil {
+		a.watchCancel()
+		<-a.watchDone
+
+		if err := a.mgmt.Close(); err != nil {
+			log.Warn().Err(err).Msg("error closing VPC Management handle")
+		}
+	}
+
 	if err := a.rpcListener.Close(); err != nil {
 		log.Warn().Err(err).Msg("error during RPC listener shutdown")
 	}
@@ -1,6 +1,10 @@
 package agent
 
-import "github.com/joyent/freebsd-vpc/db"
+import (
+	"time"
+
+	"github.com/joyent/freebsd-vpc/db"
+)
 
 type Config struct {
 	DBConfig db.Config `mapstructure:"db"`
@@ -8,5 +12,6 @@ type Config struct {
 		Addresses struct {
 			Internal string `mapstructure:"internal"`
 		} `mapstructure:"addresses"`
+		WatchInterval time.Duration `mapstructure:"watch-interval"`
 	} `mapstructure:"agent"`
 }
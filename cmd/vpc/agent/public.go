@@ -110,6 +110,7 @@ var Cmd = &command.Command{
 
 func setAgentDefaultViperOptions() error {
 	viper.SetDefault("agent.addresses.internal", "/tmp/vpc-agent.sock")
+	viper.SetDefault("agent.watch-interval", 5*time.Second)
 
 	return nil
 }
@@ -0,0 +1,114 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/joyent/freebsd-vpc/internal/topology"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName = "apply"
+	_KeyFile = config.KeyApplyFile
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "create or update the VPC objects described by a topology file",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The apply operation of vpc(8) converges the VPC objects in the kernel onto
+the VPC topology described by a topology file.  The actions displayed by
+"vpc plan" are run in dependency order.  Objects created by apply are only
+committed once every action succeeded, otherwise the changes are undone.
+
+Objects that are not part of the topology file are left alone, with the
+exception of the ports of the switches described by the topology file: ports
+that are not in the topology file are removed.`,
+		Example: `% doas vpc apply -f host.hcl`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			renderer, err := command.NewRenderer(cons)
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			topo, err := topology.Load(viper.GetString(_KeyFile))
+			if err != nil {
+				return errors.Wrap(err, "unable to load VPC topology")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			plan, err := topology.NewPlan(topo, snap)
+			if err != nil {
+				return errors.Wrap(err, "unable to plan VPC topology changes")
+			}
+
+			if plan.Empty() {
+				if !renderer.Structured() {
+					cons.Write([]byte("No changes: the VPC objects match the topology file.\n"))
+				}
+				return nil
+			}
+
+			if err := renderer.Render(plan.Table()); err != nil {
+				return err
+			}
+
+			if !renderer.Structured() {
+				cons.Write([]byte(fmt.Sprintf("Applying %d actions...", len(plan.Actions))))
+			}
+
+			err = plan.Apply(func(act topology.Action) {
+				log.Debug().Object("action", act).Msg("applying")
+			})
+			if err != nil {
+				log.Error().Err(err).Msg("VPC topology apply failed")
+				return errors.Wrap(err, "unable to apply VPC topology")
+			}
+
+			if !renderer.Structured() {
+				cons.Write([]byte("done.\n"))
+			}
+
+			log.Info().Int("actions", len(plan.Actions)).Msg("VPC topology applied")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddTopologyFile(self, _KeyFile); err != nil {
+			return errors.Wrap(err, "unable to register file flag on VPC apply")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,110 @@
+package destroy
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/joyent/freebsd-vpc/internal/topology"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName = "destroy"
+	_KeyFile = config.KeyDestroyFile
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "destroy the VPC objects described by a topology file",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The destroy operation of vpc(8) tears down the VPC objects described by a
+topology file that exist in the kernel, in the reverse order of their
+creation: ports are disconnected, EthLinks and VM NICs are destroyed, then
+ports are removed and switches are destroyed.`,
+		Example: `% doas vpc destroy -f host.hcl`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			renderer, err := command.NewRenderer(cons)
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			topo, err := topology.Load(viper.GetString(_KeyFile))
+			if err != nil {
+				return errors.Wrap(err, "unable to load VPC topology")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			plan, err := topology.NewDestroyPlan(topo, snap)
+			if err != nil {
+				return errors.Wrap(err, "unable to plan VPC topology destruction")
+			}
+
+			if plan.Empty() {
+				if !renderer.Structured() {
+					cons.Write([]byte("No changes: none of the VPC objects in the topology file exist.\n"))
+				}
+				return nil
+			}
+
+			if err := renderer.Render(plan.Table()); err != nil {
+				return err
+			}
+
+			if !renderer.Structured() {
+				cons.Write([]byte(fmt.Sprintf("Destroying VPC topology (%d actions)...", len(plan.Actions))))
+			}
+
+			err = plan.Apply(func(act topology.Action) {
+				log.Debug().Object("action", act).Msg("destroying")
+			})
+			if err != nil {
+				log.Error().Err(err).Msg("VPC topology destroy failed")
+				return errors.Wrap(err, "unable to destroy VPC topology")
+			}
+
+			if !renderer.Structured() {
+				cons.Write([]byte("done.\n"))
+			}
+
+			log.Info().Int("actions", len(plan.Actions)).Msg("VPC topology destroyed")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddTopologyFile(self, _KeyFile); err != nil {
+			return errors.Wrap(err, "unable to register file flag on VPC destroy")
+		}
+
+		return nil
+	},
+}
@@ -3,6 +3,7 @@ package destroy
 import (
 	"fmt"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/ethlink"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/joyent/freebsd-vpc/internal/command/flag"
@@ -15,8 +16,10 @@ import (
 )
 
 const (
-	cmdName      = "destroy"
-	keyEthLinkID = config.KeyEthLinkDestroyID
+	cmdName         = "destroy"
+	keyEthLinkID    = config.KeyEthLinkDestroyID
+	keyRetry        = config.KeyEthLinkDestroyRetry
+	keyRetryTimeout = config.KeyEthLinkDestroyRetryTimeout
 )
 
 var Cmd = &command.Command{
@@ -41,6 +44,10 @@ var Cmd = &command.Command{
 			return errors.Wrap(err, "unable to register EthLink ID flag on EthLink destroy")
 		}
 
+		if err := flag.AddRetry(self, keyRetry, keyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC EthLink destroy")
+		}
+
 		return nil
 	},
 }
@@ -48,6 +55,12 @@ var Cmd = &command.Command{
 func runE(_ *cobra.Command, _ []string) error {
 	cons := conswriter.GetTerminal()
 
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), keyRetry, keyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
 	cons.Write([]byte(fmt.Sprintf("Destroying VPC EthLink...")))
 
 	ethLinkID, err := flag.GetID(viper.GetViper(), keyEthLinkID)
@@ -0,0 +1,118 @@
+package id
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName    = "id"
+	_KeyObjType = config.KeyIDObjType
+	_KeyUUID    = config.KeyIDUUID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:   _CmdName,
+		Short: "generate or derive a VPC ID",
+		Long: `Print a VPC ID of the given object type.  When --uuid is given, the VPC ID is
+derived from the control-plane UUID (e.g. a VNIC's database ID) and is stable:
+the same UUID always yields the same ID for a given object type.  Otherwise a
+new random VPC ID is generated.  The object type must be the type of a VPC
+object: "any" and "meta" are rejected.`,
+		Example: `% vpc id --obj-type=vmnic --uuid=7c9e6679-7425-40de-944b-e07fc1f90ae7
+7c9e6679-7425-40de-9406-e07fc1f90ae7
+% vpc id --obj-type=vpcp --uuid=7c9e6679-7425-40de-944b-e07fc1f90ae7
+7c9e6679-7425-40de-9402-e07fc1f90ae7`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			objType, err := vpc.ParseObjType(viper.GetString(_KeyObjType))
+			if err != nil {
+				return errors.Wrap(err, "unable to parse VPC Object Type")
+			}
+
+			if !validObjType(objType) {
+				return errors.Errorf("unsupported VPC Object Type %s for a VPC ID", objType)
+			}
+
+			var id vpc.ID
+			switch uuidStr := viper.GetString(_KeyUUID); uuidStr {
+			case "":
+				id = vpc.GenID(objType)
+			default:
+				u, err := uuid.FromString(uuidStr)
+				if err != nil {
+					return errors.Wrapf(err, "unable to parse UUID %q", uuidStr)
+				}
+
+				if id, err = vpc.IDFromUUID(u, objType); err != nil {
+					return errors.Wrap(err, "unable to derive VPC ID")
+				}
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), id.String())
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		{
+			const (
+				key          = _KeyObjType
+				longName     = "obj-type"
+				shortName    = "t"
+				defaultValue = ""
+				description  = "VPC Object Type of the ID (e.g. vpcsw, vpcp, vmnic)"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			self.Cobra.MarkFlagRequired(longName)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = _KeyUUID
+				longName     = "uuid"
+				shortName    = "u"
+				defaultValue = ""
+				description  = "control-plane UUID to derive the VPC ID from"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
+
+// validObjType returns true if objType is the type of a VPC object, i.e. not
+// an invalid, wildcard, or unknown type.
+func validObjType(objType vpc.ObjType) bool {
+	for _, t := range vpc.ObjTypes() {
+		if t == objType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package id
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/spf13/viper"
+)
+
+// run runs the id command with the given flags and returns its output.
+func run(objType, uuidStr string) (string, error) {
+	viper.Set(config.KeyIDObjType, objType)
+	viper.Set(config.KeyIDUUID, uuidStr)
+	defer viper.Reset()
+
+	var buf bytes.Buffer
+	Cmd.Cobra.SetOutput(&buf)
+	defer Cmd.Cobra.SetOutput(nil)
+
+	err := Cmd.Cobra.RunE(Cmd.Cobra, nil)
+	return strings.TrimSpace(buf.String()), err
+}
+
+func TestID_Generate(t *testing.T) {
+	for _, objType := range vpc.ObjTypes() {
+		out, err := run(objType.String(), "")
+		if err != nil {
+			t.Errorf("%s: unable to generate an ID: %v", objType, err)
+			continue
+		}
+
+		id, err := vpc.ParseID(out)
+		switch {
+		case err != nil:
+			t.Errorf("%s: unable to parse generated ID %q: %v", objType, out, err)
+		case id.ObjType != objType:
+			t.Errorf("%s: generated ID %s has type %s", objType, id, id.ObjType)
+		}
+	}
+}
+
+func TestID_FromUUID(t *testing.T) {
+	const uuidStr = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+
+	tests := []struct {
+		objType string
+		want    string
+	}{
+		{"vmnic", "7c9e6679-7425-40de-9406-e07fc1f90ae7"},
+		{"vpcp", "7c9e6679-7425-40de-9402-e07fc1f90ae7"},
+	}
+
+	for _, test := range tests {
+		out, err := run(test.objType, uuidStr)
+		switch {
+		case err != nil:
+			t.Errorf("%s: unable to derive an ID: %v", test.objType, err)
+		case out != test.want:
+			t.Errorf("%s: expected %s, got %s", test.objType, test.want, out)
+		}
+	}
+
+	if _, err := run("vmnic", "bogus"); err == nil || !strings.Contains(err.Error(), `unable to parse UUID "bogus"`) {
+		t.Errorf("expected a UUID parse error, got %v", err)
+	}
+}
+
+func TestID_InvalidObjType(t *testing.T) {
+	tests := []struct {
+		objType string
+		errStr  string
+	}{
+		{"any", "unsupported VPC Object Type any for a VPC ID"},
+		{"meta", "unsupported VPC Object Type meta for a VPC ID"},
+		{"invalid", "unsupported VPC Object Type invalid for a VPC ID"},
+		{"objtype(0x2a)", "unsupported VPC Object Type objtype(0x2a) for a VPC ID"},
+		{"bogus", `unsupported VPC Object Type "bogus"`},
+	}
+
+	for _, uuidStr := range []string{"", "7c9e6679-7425-40de-944b-e07fc1f90ae7"} {
+		for _, test := range tests {
+			out, err := run(test.objType, uuidStr)
+			if err == nil || !strings.Contains(err.Error(), test.errStr) {
+				t.Errorf("%s (uuid %q): expected an error containing %q, got %v", test.objType, uuidStr, test.errStr, err)
+			}
+
+			if out != "" {
+				t.Errorf("%s (uuid %q): unexpected output %q", test.objType, uuidStr, out)
+			}
+		}
+	}
+}
@@ -1,12 +1,34 @@
 package list
 
 import (
+	"net"
+	"sort"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpctest"
 	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/sean-/conswriter"
 	"github.com/spf13/cobra"
 )
 
 const _CmdName = "list"
 
+var columns = []command.Column{
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "index", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "mtu", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "mac", Align: tablewriter.ALIGN_LEFT},
+	{Name: "flags", Align: tablewriter.ALIGN_LEFT, Wide: true},
+}
+
+// ifaceRecord is a network interface in the output of list.
+type ifaceRecord net.Interface
+
+func (r ifaceRecord) Values() []interface{} {
+	return []interface{}{r.Name, r.Index, r.MTU, r.HardwareAddr, r.Flags}
+}
+
 var Cmd = &command.Command{
 	Name: _CmdName,
 	Cobra: &cobra.Command{
@@ -20,53 +42,30 @@ var Cmd = &command.Command{
 		},
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// mib := []int{unix.CTL_KERN, unix.KERN_OSTYPE}
-			// buf := [256]byte{}
-			// n := unsafe.Sizeof(buf)
-			// if err := unix.SysctlRaw("kern.hostname", mib, &uname.Sysname[0], &n, nil, 0); err != nil {
-			// 	return err
-			// }
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
 
-			// log.Info().Str("hostname", h).Msg("list")
-
-			return nil
-			// tritonClientConfig, err := api.InitConfig()
-			// if err != nil {
-			// 	return err
-			// }
+			ifaces, err := vpctest.GetAllInterfaces()
+			if err != nil {
+				return errors.Wrap(err, "unable to get all interfaces")
+			}
 
-			// client, err := tritonClientConfig.GetComputeClient()
-			// if err != nil {
-			// 	return err
-			// }
+			records := make([]command.Record, 0, len(ifaces))
+			for _, iface := range ifaces {
+				records = append(records, ifaceRecord(iface))
+			}
+			sort.Slice(records, func(i, j int) bool {
+				return records[i].(ifaceRecord).Name < records[j].(ifaceRecord).Name
+			})
 
-			// instances, err := client.Instances().List(context.Background(), &compute.ListInstancesInput{})
-			// if err != nil {
-			// 	return err
-			// }
-
-			// table := tablewriter.NewWriter(cons)
-			// table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-			// table.SetHeaderLine(false)
-			// table.SetAutoFormatHeaders(true)
-
-			// table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
-			// table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-			// table.SetCenterSeparator("")
-			// table.SetColumnSeparator("")
-			// table.SetRowSeparator("")
-
-			// table.SetHeader([]string{"id", "name", "image", "package"})
-
-			// var numInstances uint
-			// for _, instance := range instances {
-			// 	table.Append([]string{instance.ID, instance.Name, instance.Image, instance.Package})
-			// 	numInstances++
-			// }
-
-			// table.Render()
-
-			return nil
+			return renderer.Render(command.Table{
+				Name:    "interfaces",
+				Columns: columns,
+				Records: records,
+				Total:   true,
+			})
 		},
 	},
 
@@ -2,10 +2,13 @@ package list
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"sort"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
@@ -16,6 +19,7 @@ import (
 	"github.com/sean-/conswriter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -23,6 +27,8 @@ const (
 	keyObjCounts = config.KeyListObjCounts
 	keySortBy    = config.KeyListObjSortBy
 	keyType      = config.KeyListObjType
+	keyWatch     = config.KeyListWatch
+	keyWatchIntv = config.KeyListWatchIntv
 )
 
 var Cmd = &command.Command{
@@ -50,16 +56,29 @@ and their respective VPC IDs.`,
  vpcp     fd436f9c-1f77-11e8-8002-0cc47a6c7d1e  vpcp0
  vpcsw    da64c3f3-095d-91e5-df01-5aabcfc52468  vpcsw0
 
-   TOTAL                    7`,
+   TOTAL                    7
+
+% vpc list --watch
+ TIME                  EVENT      TYPE   ID                                    UNIT NAME
+ 2018-03-08T21:02:11Z  created    vpcsw  da64c3f3-095d-91e5-df01-5aabcfc52468  vpcsw0
+ 2018-03-08T21:02:14Z  created    vpcp   fd436f9c-1f77-11e8-8002-0cc47a6c7d1e  vpcp0
+ 2018-03-08T21:02:20Z  destroyed  vpcp   fd436f9c-1f77-11e8-8002-0cc47a6c7d1e  vpcp0`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cons := conswriter.GetTerminal()
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			if viper.GetBool(keyWatch) {
+				return watchObjects(renderer)
+			}
 
 			if viper.GetBool(keyObjCounts) {
-				return listTypeCount(cons)
+				return listTypeCount(renderer)
 			}
 
-			return listTypeIDs(cons)
+			return listTypeIDs(renderer)
 		},
 	},
 
@@ -122,94 +141,139 @@ and their respective VPC IDs.`,
 			viper.SetDefault(key, defaultValue)
 		}
 
+		{
+			const (
+				key          = keyWatch
+				longName     = "watch"
+				shortName    = "w"
+				defaultValue = false
+				description  = "watch for VPC objects being created, destroyed, or reconfigured"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = keyWatchIntv
+				longName     = "watch-interval"
+				shortName    = ""
+				defaultValue = 2 * time.Second
+				description  = "interval between polls of the VPC objects when watching"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.DurationP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
 		return nil
 	},
 }
 
-func listTypeCount(cons conswriter.ConsoleWriter) error {
-	table := tablewriter.NewWriter(cons)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeaderLine(false)
-	table.SetAutoFormatHeaders(true)
+var (
+	countColumns = []command.Column{
+		{Name: "name", Align: tablewriter.ALIGN_LEFT},
+		{Name: "count", Align: tablewriter.ALIGN_RIGHT},
+	}
+
+	objColumns = []command.Column{
+		{Name: "type", Align: tablewriter.ALIGN_LEFT},
+		{Name: "id", Align: tablewriter.ALIGN_RIGHT},
+		{Name: "unit-name", Align: tablewriter.ALIGN_LEFT},
+		{Name: "unit", Align: tablewriter.ALIGN_RIGHT, Wide: true},
+	}
+
+	eventColumns = []command.Column{
+		{Name: "time", Align: tablewriter.ALIGN_LEFT, Width: len(time.RFC3339)},
+		{Name: "event", Align: tablewriter.ALIGN_LEFT, Width: len("reconfigured")},
+		{Name: "type", Align: tablewriter.ALIGN_LEFT, Width: len("ethlink")},
+		{Name: "id", Align: tablewriter.ALIGN_LEFT, Width: 36},
+		{Name: "unit-name", Align: tablewriter.ALIGN_LEFT, Width: 12},
+		{Name: "unit", Align: tablewriter.ALIGN_RIGHT, Wide: true, Width: 4},
+	}
+)
 
-	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
-	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
+// countRecord is the number of objects of a VPC type.
+type countRecord struct {
+	objType vpc.ObjType
+	count   uint32
+}
 
-	table.SetHeader([]string{"name", "count"})
+func (r countRecord) Values() []interface{} {
+	return []interface{}{r.objType, r.count}
+}
 
+// objRecord is a VPC object.
+type objRecord struct {
+	objType  vpc.ObjType
+	id       vpc.ID
+	unitName string
+	unitNo   uint32
+}
+
+func (r objRecord) Values() []interface{} {
+	return []interface{}{r.objType, r.id, r.unitName, r.unitNo}
+}
+
+// eventRecord is a VPC object lifecycle event.
+type eventRecord struct {
+	time  string
+	event mgmt.EventType
+	obj   mgmt.Object
+}
+
+func (r eventRecord) Values() []interface{} {
+	return []interface{}{r.time, r.event, r.obj.ObjType, r.obj.ID, r.obj.UnitName, r.obj.UnitNo}
+}
+
+func listTypeCount(renderer *command.Renderer) error {
 	mgr, err := mgmt.New(nil)
 	if err != nil {
 		return errors.Wrapf(err, "unable to open VPC Management handle")
 	}
 	defer mgr.Close()
 
-	var numTypes int64
-	for _, objType := range vpc.ObjTypes() {
+	objTypes := vpc.ObjTypes()
+	records := make([]command.Record, 0, len(objTypes))
+	for _, objType := range objTypes {
 		count, err := mgr.CountType(objType)
 		if err != nil {
 			return errors.Wrapf(err, "unable to count object type %s", objType)
 		}
 
-		table.Append([]string{
-			objType.String(),
-			strconv.FormatInt(int64(count), 10),
+		records = append(records, countRecord{
+			objType: objType,
+			count:   count,
 		})
-		numTypes++
 	}
 
-	table.SetFooter([]string{"total", strconv.FormatInt(numTypes, 10)})
-
-	table.Render()
-
-	return nil
+	return renderer.Render(command.Table{
+		Columns: countColumns,
+		Records: records,
+		Total:   true,
+	})
 }
 
-func listTypeIDs(cons conswriter.ConsoleWriter) error {
-	table := tablewriter.NewWriter(cons)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeaderLine(false)
-	table.SetAutoFormatHeaders(true)
-
-	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT})
-	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-
-	table.SetHeader([]string{"type", "id", "unit name"})
-
+func listTypeIDs(renderer *command.Renderer) error {
 	mgr, err := mgmt.New(nil)
 	if err != nil {
 		return errors.Wrapf(err, "unable to open VPC Management handle")
 	}
 	defer mgr.Close()
 
-	var objTypes []vpc.ObjType
-	{
-		objTypes = vpc.ObjTypes()
-		sort.SliceStable(objTypes, func(i, j int) bool { return objTypes[i].String() < objTypes[j].String() })
-
-		wantObjTypeStr := viper.GetString(keyType)
-		if objTypeStr := strings.ToLower(wantObjTypeStr); objTypeStr != "all" {
-			var found bool
-			for _, objType := range objTypes {
-				if objTypeStr == strings.ToLower(objType.String()) {
-					found = true
-					objTypes = []vpc.ObjType{objType}
-					break
-				}
-			}
-
-			if !found {
-				return errors.Errorf("unsupported VPC Object Type %q", wantObjTypeStr)
-			}
-		}
+	objTypes, err := selectObjTypes()
+	if err != nil {
+		return err
 	}
 
-	var numIDs int64
+	records := make([]command.Record, 0)
 	for _, objType := range objTypes {
 		objHeaders, err := mgr.GetAllIDs(objType)
 		if err != nil {
@@ -227,18 +291,108 @@ func listTypeIDs(cons conswriter.ConsoleWriter) error {
 		}
 
 		for _, hdr := range objHeaders {
-			table.Append([]string{
-				hdr.ObjType().String(),
-				hdr.ID().String(),
-				hdr.UnitName(),
+			records = append(records, objRecord{
+				objType:  hdr.ObjType(),
+				id:       hdr.ID(),
+				unitName: hdr.UnitName(),
+				unitNo:   hdr.UnitNo(),
 			})
-			numIDs++
 		}
 	}
 
-	table.SetFooter([]string{"total", strconv.FormatInt(numIDs, 10), ""})
+	return renderer.Render(command.Table{
+		Columns: objColumns,
+		Records: records,
+		Total:   true,
+	})
+}
+
+// watchObjects prints VPC object lifecycle events until interrupted.  The
+// objects present at startup are reported as created.
+func watchObjects(renderer *command.Renderer) error {
+	objTypes, err := selectObjTypes()
+	if err != nil {
+		return err
+	}
+
+	wantTypes := make(map[vpc.ObjType]bool, len(objTypes))
+	for _, objType := range objTypes {
+		wantTypes[objType] = true
+	}
+
+	stream, err := renderer.Stream(eventColumns)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := mgmt.New(nil)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open VPC Management handle")
+	}
+	defer mgr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, unix.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	events, err := mgr.Watch(ctx, viper.GetDuration(keyWatchIntv))
+	if err != nil {
+		return errors.Wrap(err, "unable to watch VPC objects")
+	}
+
+	for ev := range events {
+		if ev.Type == mgmt.EventError {
+			return errors.Wrap(ev.Err, "unable to watch VPC objects")
+		}
+
+		if !wantTypes[ev.Object.ObjType] {
+			continue
+		}
 
-	table.Render()
+		ts := ev.Time
+		if viper.GetBool(config.KeyUseUTC) {
+			ts = ts.UTC()
+		}
+
+		err := stream.Write(eventRecord{
+			time:  ts.Format(time.RFC3339),
+			event: ev.Type,
+			obj:   ev.Object,
+		})
+		if err != nil {
+			return errors.Wrap(err, "unable to write VPC object event")
+		}
+	}
 
 	return nil
 }
+
+// selectObjTypes returns the object types selected with --obj-type, sorted by
+// name.
+func selectObjTypes() ([]vpc.ObjType, error) {
+	objTypes := vpc.ObjTypes()
+	sort.SliceStable(objTypes, func(i, j int) bool { return objTypes[i].String() < objTypes[j].String() })
+
+	wantObjTypeStr := viper.GetString(keyType)
+	if objTypeStr := strings.ToLower(wantObjTypeStr); objTypeStr != "all" {
+		for _, objType := range objTypes {
+			if objTypeStr == strings.ToLower(objType.String()) {
+				return []vpc.ObjType{objType}, nil
+			}
+		}
+
+		return nil, errors.Errorf("unsupported VPC Object Type %q", wantObjTypeStr)
+	}
+
+	return objTypes, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/rs/zerolog/log"
 	"github.com/sean-/conswriter"
 	"github.com/sean-/sysexits"
@@ -25,14 +26,44 @@ func realmain() int {
 		p.Wait()
 	}()
 
-	if err := Execute(); err != nil {
+	err := Execute()
+
+	// Only populated when VPC_TRACK_HANDLES is set in the environment.
+	for _, hi := range vpc.OpenHandles() {
+		log.Warn().Object("handle", hi).Msg("VPC handle left open at exit")
+	}
+
+	if err != nil {
 		log.Error().Err(err).Msg("unable to run")
-		os.Exit(sysexits.Software)
+		os.Exit(exitCode(err))
 	}
 
 	return sysexits.OK
 }
 
+// exitCode maps the kind of a VPC error to a sysexits(3) exit code so that
+// scripts can branch on the failure.
+func exitCode(err error) int {
+	switch vpc.KindOf(err) {
+	case vpc.ErrNotFound:
+		return sysexits.NoInput
+	case vpc.ErrExists:
+		return sysexits.CantCreate
+	case vpc.ErrBusy:
+		return sysexits.TempFail
+	case vpc.ErrPermission:
+		return sysexits.NoPerm
+	case vpc.ErrUnsupported:
+		return sysexits.Unavailable
+	case vpc.ErrABI:
+		return sysexits.Protocol
+	case vpc.ErrInvalid:
+		return sysexits.DataErr
+	default:
+		return sysexits.Software
+	}
+}
+
 func main() {
 	os.Exit(realmain())
 }
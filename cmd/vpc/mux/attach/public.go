@@ -0,0 +1,87 @@
+package attach
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcmux"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName   = "attach"
+	_KeyMuxID  = config.KeyMuxAttachMuxID
+	_KeyPortID = config.KeyMuxAttachPortID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "attach a VPC Switch Port to a VPC mux",
+		Long:         "attach a VPC Switch Port to a VPC mux.  Traffic from the port is encapsulated using the VNI of the port.",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Attaching VPC Switch Port to VPC Mux...")))
+
+			muxID, err := flag.GetID(viper.GetViper(), _KeyMuxID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Mux ID")
+			}
+
+			portID, err := flag.GetPortID(viper.GetViper(), _KeyPortID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get switch port ID")
+			}
+
+			muxCfg := vpcmux.Config{
+				ID:        muxID,
+				Writeable: true,
+			}
+
+			vpcMux, err := vpcmux.Open(muxCfg)
+			if err != nil {
+				log.Error().Err(err).Object("mux-id", muxID).Msg("VPC Mux open failed")
+				return errors.Wrap(err, "unable to open VPC Mux")
+			}
+			defer vpcMux.Close()
+
+			if err := vpcMux.PortAdd(portID); err != nil {
+				log.Error().Err(err).Object("mux-id", muxID).Object("port-id", portID).Msg("vpc mux port add failed")
+				return errors.Wrap(err, "unable to attach VPC Switch Port to VPC Mux")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Object("mux-id", muxID).Object("port-id", portID).Msg("VPC Switch Port attached to VPC Mux")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddMuxID(self, _KeyMuxID, true); err != nil {
+			return errors.Wrap(err, "unable to register Mux ID flag on VPC Mux attach")
+		}
+
+		if err := flag.AddPortID(self, _KeyPortID, true); err != nil {
+			return errors.Wrap(err, "unable to register Port ID flag on VPC Mux attach")
+		}
+
+		return nil
+	},
+}
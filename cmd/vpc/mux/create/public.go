@@ -0,0 +1,118 @@
+package create
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcmux"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName       = "create"
+	_KeyEthLinkID  = config.KeyMuxCreateEthLinkID
+	_KeyListenAddr = config.KeyMuxCreateListenAddr
+	_KeyMuxID      = config.KeyMuxCreateMuxID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "create a VPC mux bound to an underlay ethlink",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Creating VPC Mux...")))
+
+			id, err := flag.GetID(viper.GetViper(), _KeyMuxID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC ID")
+			}
+
+			ethLinkID, err := flag.GetID(viper.GetViper(), _KeyEthLinkID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get ethlink ID")
+			}
+
+			listenAddr, err := flag.GetUDPAddr(viper.GetViper(), _KeyListenAddr)
+			if err != nil {
+				return errors.Wrap(err, "unable to get listen address")
+			}
+
+			muxCfg := vpcmux.Config{
+				ID: id,
+			}
+
+			vpcMux, err := vpcmux.Create(muxCfg)
+			if err != nil {
+				log.Error().Err(err).Str("id", id.String()).Msg("vpcmux create failed")
+				return errors.Wrap(err, "unable to create VPC Mux")
+			}
+			defer vpcMux.Close()
+
+			if err := vpcMux.UnderlaySet(ethLinkID); err != nil {
+				log.Error().Err(err).Str("id", id.String()).Object("ethlink-id", ethLinkID).Msg("vpcmux underlay set failed")
+				return errors.Wrap(err, "unable to set VPC Mux underlay")
+			}
+
+			if err := vpcMux.Listen(listenAddr); err != nil {
+				log.Error().Err(err).Str("id", id.String()).Str("listen-addr", listenAddr.String()).Msg("vpcmux listen failed")
+				return errors.Wrap(err, "unable to set VPC Mux listen address")
+			}
+
+			if err := vpcMux.Commit(); err != nil {
+				log.Error().Err(err).Str("id", id.String()).Msg("vpcmux commit failed")
+				return errors.Wrap(err, "unable to commit VPC Mux")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Str("id", id.String()).Object("ethlink-id", ethLinkID).Str("listen-addr", listenAddr.String()).Msg("vpcmux created")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddMuxID(self, _KeyMuxID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Mux create")
+		}
+
+		if err := flag.AddEthLinkID(self, _KeyEthLinkID, true); err != nil {
+			return errors.Wrap(err, "unable to register EthLink ID flag on VPC Mux create")
+		}
+
+		{
+			const (
+				key          = _KeyListenAddr
+				longName     = "listen-addr"
+				shortName    = "L"
+				defaultValue = ""
+			)
+			description := fmt.Sprintf("local address to receive encapsulated traffic on (port defaults to %d)", vpcmux.DefaultListenPort)
+
+			flags := self.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			self.Cobra.MarkFlagRequired(longName)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
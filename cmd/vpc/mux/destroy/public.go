@@ -0,0 +1,91 @@
+package destroy
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcmux"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName         = "destroy"
+	_KeyMuxID        = config.KeyMuxDestroyMuxID
+	_KeyRetry        = config.KeyMuxDestroyRetry
+	_KeyRetryTimeout = config.KeyMuxDestroyRetryTimeout
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+	Cobra: &cobra.Command{
+		Use:              _CmdName,
+		Aliases:          []string{"rm", "del", "delete"},
+		TraverseChildren: true,
+		Short:            "destroy a VPC mux",
+		SilenceUsage:     true,
+		Args:             cobra.NoArgs,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: runE,
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddMuxID(self, _KeyMuxID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Mux destroy")
+		}
+
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC Mux destroy")
+		}
+
+		return nil
+	},
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	cons := conswriter.GetTerminal()
+
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
+	cons.Write([]byte(fmt.Sprintf("Destroying VPC Mux...")))
+
+	id, err := flag.GetID(viper.GetViper(), _KeyMuxID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get VPC ID")
+	}
+
+	muxCfg := vpcmux.Config{
+		ID:        id,
+		Writeable: true,
+	}
+
+	log.Info().Object("cfg", muxCfg).Str("op", "destroy").Msg("vpc_ctl")
+
+	vpcMux, err := vpcmux.Open(muxCfg)
+	if err != nil {
+		return errors.Wrap(err, "unable to open VPC Mux")
+	}
+	defer vpcMux.Close()
+
+	if err := vpcMux.Destroy(); err != nil {
+		return errors.Wrap(err, "unable to destroy VPC Mux")
+	}
+
+	cons.Write([]byte("done.\n"))
+
+	return nil
+}
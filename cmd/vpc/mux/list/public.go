@@ -0,0 +1,9 @@
+package list
+
+import (
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/config"
+)
+
+var Cmd = command.NewObjectList(vpc.ObjTypeMux, "list VPC muxes", config.KeyMuxListSortBy)
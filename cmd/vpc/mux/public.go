@@ -0,0 +1,37 @@
+package mux
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/mux/attach"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/mux/create"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/mux/destroy"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/mux/list"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const _CmdName = "mux"
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:   _CmdName,
+		Short: "VPC mux management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			attach.Cmd,
+			create.Cmd,
+			destroy.Cmd,
+			list.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", _CmdName)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,111 @@
+package connect
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName      = "connect"
+	_KeyEthLinkID = config.KeyNATConnectEthLinkID
+	_KeyNATID     = config.KeyNATConnectNATID
+	_KeyPortID    = config.KeyNATConnectPortID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "connect a VPC NAT to a VPC Switch Port and an EthLink uplink",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if viper.GetString(_KeyPortID) == "" && viper.GetString(_KeyEthLinkID) == "" {
+				return errors.New("at least one of --port-id or --ethlink-id must be specified")
+			}
+
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Connecting VPC NAT...")))
+
+			natID, err := flag.GetID(viper.GetViper(), _KeyNATID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT ID")
+			}
+
+			natCfg := vpcnat.Config{
+				ID:        natID,
+				Writeable: true,
+			}
+
+			vpcNAT, err := vpcnat.Open(natCfg)
+			if err != nil {
+				log.Error().Err(err).Object("nat-id", natID).Msg("VPC NAT open failed")
+				return errors.Wrap(err, "unable to open VPC NAT")
+			}
+			defer vpcNAT.Close()
+
+			if viper.GetString(_KeyPortID) != "" {
+				portID, err := flag.GetPortID(viper.GetViper(), _KeyPortID)
+				if err != nil {
+					return errors.Wrap(err, "unable to get switch port ID")
+				}
+
+				if err := vpcNAT.PortSet(portID); err != nil {
+					log.Error().Err(err).Object("nat-id", natID).Object("port-id", portID).Msg("vpc nat port set failed")
+					return errors.Wrap(err, "unable to connect VPC Switch Port to VPC NAT")
+				}
+
+				log.Info().Object("nat-id", natID).Object("port-id", portID).Msg("VPC Switch Port connected to VPC NAT")
+			}
+
+			if viper.GetString(_KeyEthLinkID) != "" {
+				ethLinkID, err := flag.GetID(viper.GetViper(), _KeyEthLinkID)
+				if err != nil {
+					return errors.Wrap(err, "unable to get ethlink ID")
+				}
+
+				if err := vpcNAT.UplinkSet(ethLinkID); err != nil {
+					log.Error().Err(err).Object("nat-id", natID).Object("ethlink-id", ethLinkID).Msg("vpc nat uplink set failed")
+					return errors.Wrap(err, "unable to set VPC NAT uplink")
+				}
+
+				log.Info().Object("nat-id", natID).Object("ethlink-id", ethLinkID).Msg("VPC NAT uplink set")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddNATID(self, _KeyNATID, true); err != nil {
+			return errors.Wrap(err, "unable to register NAT ID flag on VPC NAT connect")
+		}
+
+		if err := flag.AddPortID(self, _KeyPortID, false); err != nil {
+			return errors.Wrap(err, "unable to register Port ID flag on VPC NAT connect")
+		}
+
+		if err := flag.AddEthLinkID(self, _KeyEthLinkID, false); err != nil {
+			return errors.Wrap(err, "unable to register EthLink ID flag on VPC NAT connect")
+		}
+
+		return nil
+	},
+}
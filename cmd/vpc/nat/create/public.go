@@ -0,0 +1,75 @@
+package create
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName  = "create"
+	_KeyNATID = config.KeyNATCreateNATID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "create a VPC NAT",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Creating VPC NAT...")))
+
+			id, err := flag.GetID(viper.GetViper(), _KeyNATID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC ID")
+			}
+
+			natCfg := vpcnat.Config{
+				ID: id,
+			}
+
+			vpcNAT, err := vpcnat.Create(natCfg)
+			if err != nil {
+				log.Error().Err(err).Str("id", id.String()).Msg("vpcnat create failed")
+				return errors.Wrap(err, "unable to create VPC NAT")
+			}
+			defer vpcNAT.Close()
+
+			if err := vpcNAT.Commit(); err != nil {
+				log.Error().Err(err).Str("id", id.String()).Msg("vpcnat commit failed")
+				return errors.Wrap(err, "unable to commit VPC NAT")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Str("id", id.String()).Msg("vpcnat created")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddNATID(self, _KeyNATID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC NAT create")
+		}
+
+		return nil
+	},
+}
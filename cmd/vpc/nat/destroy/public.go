@@ -0,0 +1,91 @@
+package destroy
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName         = "destroy"
+	_KeyNATID        = config.KeyNATDestroyNATID
+	_KeyRetry        = config.KeyNATDestroyRetry
+	_KeyRetryTimeout = config.KeyNATDestroyRetryTimeout
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+	Cobra: &cobra.Command{
+		Use:              _CmdName,
+		Aliases:          []string{"rm", "del", "delete"},
+		TraverseChildren: true,
+		Short:            "destroy a VPC NAT",
+		SilenceUsage:     true,
+		Args:             cobra.NoArgs,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: runE,
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddNATID(self, _KeyNATID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC NAT destroy")
+		}
+
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC NAT destroy")
+		}
+
+		return nil
+	},
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	cons := conswriter.GetTerminal()
+
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
+	cons.Write([]byte(fmt.Sprintf("Destroying VPC NAT...")))
+
+	id, err := flag.GetID(viper.GetViper(), _KeyNATID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get VPC ID")
+	}
+
+	natCfg := vpcnat.Config{
+		ID:        id,
+		Writeable: true,
+	}
+
+	log.Info().Object("cfg", natCfg).Str("op", "destroy").Msg("vpc_ctl")
+
+	vpcNAT, err := vpcnat.Open(natCfg)
+	if err != nil {
+		return errors.Wrap(err, "unable to open VPC NAT")
+	}
+	defer vpcNAT.Close()
+
+	if err := vpcNAT.Destroy(); err != nil {
+		return errors.Wrap(err, "unable to destroy VPC NAT")
+	}
+
+	cons.Write([]byte("done.\n"))
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package get
+
+import (
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName  = "get"
+	_KeyNATID = config.KeyNATGetNATID
+)
+
+var (
+	natColumns = []command.Column{
+		{Name: "id", Align: tablewriter.ALIGN_LEFT},
+		{Name: "port", Align: tablewriter.ALIGN_LEFT},
+		{Name: "uplink", Align: tablewriter.ALIGN_LEFT},
+	}
+
+	ruleColumns = []command.Column{
+		{Name: "type", Align: tablewriter.ALIGN_LEFT},
+		{Name: "protocol", Align: tablewriter.ALIGN_LEFT},
+		{Name: "match", Align: tablewriter.ALIGN_LEFT},
+		{Name: "port", Align: tablewriter.ALIGN_RIGHT},
+		{Name: "translate", Align: tablewriter.ALIGN_LEFT},
+		{Name: "translate-port", Align: tablewriter.ALIGN_RIGHT},
+	}
+)
+
+// natRecord is a VPC NAT in the output of get.
+type natRecord struct {
+	id     vpc.ID
+	port   string
+	uplink string
+}
+
+func (r natRecord) Values() []interface{} {
+	return []interface{}{r.id, r.port, r.uplink}
+}
+
+// ruleRecord is a VPC NAT translation rule in the output of get.
+type ruleRecord vpcnat.Rule
+
+func (r ruleRecord) Values() []interface{} {
+	return []interface{}{r.Type, r.Protocol, r.Match, portValue(r.Port), r.Translate, portValue(r.TranslatePort)}
+}
+
+// portValue returns nil for an unset port so that it is rendered as "-" in a
+// table and as null in JSON and YAML.
+func portValue(p uint16) interface{} {
+	if p == 0 {
+		return nil
+	}
+
+	return p
+}
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "get VPC NAT information and translation rules",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			id, err := flag.GetID(viper.GetViper(), _KeyNATID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT ID")
+			}
+
+			natCfg := vpcnat.Config{
+				ID:     id,
+				Rights: vpc.RightsReadOnly,
+			}
+			vpcNAT, err := vpcnat.Open(natCfg)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC NAT")
+			}
+			defer vpcNAT.Close()
+
+			portID, err := vpcNAT.Port()
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT port")
+			}
+
+			port := "none"
+			if portID != (vpc.ID{}) {
+				port = portID.String()
+			}
+
+			uplinkID, err := vpcNAT.Uplink()
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT uplink")
+			}
+
+			uplink := "none"
+			if uplinkID != (vpc.ID{}) {
+				uplink = uplinkID.String()
+			}
+
+			rules, err := vpcNAT.Rules()
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT rules")
+			}
+
+			natTable := command.Table{
+				Name:    "nat",
+				Columns: natColumns,
+				Records: []command.Record{natRecord{
+					id:     id,
+					port:   port,
+					uplink: uplink,
+				}},
+			}
+
+			ruleRecords := make([]command.Record, 0, len(rules))
+			for _, r := range rules {
+				ruleRecords = append(ruleRecords, ruleRecord(r))
+			}
+
+			ruleTable := command.Table{
+				Name:    "rules",
+				Columns: ruleColumns,
+				Records: ruleRecords,
+				Total:   true,
+			}
+
+			// Only structured output reports an empty set of rules.
+			if len(rules) == 0 && !renderer.Structured() {
+				return renderer.Render(natTable)
+			}
+
+			return renderer.Render(natTable, ruleTable)
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddNATID(self, _KeyNATID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC NAT get")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,41 @@
+package nat
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/connect"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/create"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/destroy"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/get"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/list"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/rule"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const _CmdName = "nat"
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:   _CmdName,
+		Short: "VPC NAT management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			connect.Cmd,
+			create.Cmd,
+			destroy.Cmd,
+			get.Cmd,
+			list.Cmd,
+			rule.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", _CmdName)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,94 @@
+package add
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName  = "add"
+	_KeyNATID = config.KeyNATRuleAddNATID
+)
+
+var _RuleCfg = flag.NATRuleCfg{
+	Type:          config.KeyNATRuleAddType,
+	Protocol:      config.KeyNATRuleAddProtocol,
+	Match:         config.KeyNATRuleAddMatch,
+	Port:          config.KeyNATRuleAddPort,
+	Translate:     config.KeyNATRuleAddTranslate,
+	TranslatePort: config.KeyNATRuleAddTranslatePort,
+}
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "add a translation rule to a VPC NAT",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Adding VPC NAT rule...")))
+
+			natID, err := flag.GetID(viper.GetViper(), _KeyNATID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT ID")
+			}
+
+			rule, err := flag.GetNATRule(viper.GetViper(), _RuleCfg)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT rule")
+			}
+
+			natCfg := vpcnat.Config{
+				ID:        natID,
+				Writeable: true,
+			}
+
+			vpcNAT, err := vpcnat.Open(natCfg)
+			if err != nil {
+				log.Error().Err(err).Object("nat-id", natID).Msg("VPC NAT open failed")
+				return errors.Wrap(err, "unable to open VPC NAT")
+			}
+			defer vpcNAT.Close()
+
+			if err := vpcNAT.RuleAdd(rule); err != nil {
+				log.Error().Err(err).Object("nat-id", natID).Object("rule", rule).Msg("vpc nat rule add failed")
+				return errors.Wrap(err, "unable to add VPC NAT rule")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Object("nat-id", natID).Object("rule", rule).Msg("VPC NAT rule added")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddNATID(self, _KeyNATID, true); err != nil {
+			return errors.Wrap(err, "unable to register NAT ID flag on VPC NAT rule add")
+		}
+
+		if err := flag.AddNATRule(self, _RuleCfg); err != nil {
+			return errors.Wrap(err, "unable to register rule flags on VPC NAT rule add")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,33 @@
+package rule
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/rule/add"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat/rule/remove"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const _CmdName = "rule"
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:   _CmdName,
+		Short: "VPC NAT rule management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			add.Cmd,
+			remove.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", _CmdName)
+		}
+
+		return nil
+	},
+}
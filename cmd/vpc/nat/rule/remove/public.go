@@ -0,0 +1,95 @@
+package remove
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName  = "remove"
+	_KeyNATID = config.KeyNATRuleRemoveNATID
+)
+
+var _RuleCfg = flag.NATRuleCfg{
+	Type:          config.KeyNATRuleRemoveType,
+	Protocol:      config.KeyNATRuleRemoveProtocol,
+	Match:         config.KeyNATRuleRemoveMatch,
+	Port:          config.KeyNATRuleRemovePort,
+	Translate:     config.KeyNATRuleRemoveTranslate,
+	TranslatePort: config.KeyNATRuleRemoveTranslatePort,
+}
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Aliases:      []string{"rm", "del", "delete"},
+		Short:        "remove a translation rule from a VPC NAT",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Removing VPC NAT rule...")))
+
+			natID, err := flag.GetID(viper.GetViper(), _KeyNATID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT ID")
+			}
+
+			rule, err := flag.GetNATRule(viper.GetViper(), _RuleCfg)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC NAT rule")
+			}
+
+			natCfg := vpcnat.Config{
+				ID:        natID,
+				Writeable: true,
+			}
+
+			vpcNAT, err := vpcnat.Open(natCfg)
+			if err != nil {
+				log.Error().Err(err).Object("nat-id", natID).Msg("VPC NAT open failed")
+				return errors.Wrap(err, "unable to open VPC NAT")
+			}
+			defer vpcNAT.Close()
+
+			if err := vpcNAT.RuleRemove(rule); err != nil {
+				log.Error().Err(err).Object("nat-id", natID).Object("rule", rule).Msg("vpc nat rule remove failed")
+				return errors.Wrap(err, "unable to remove VPC NAT rule")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Object("nat-id", natID).Object("rule", rule).Msg("VPC NAT rule removed")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddNATID(self, _KeyNATID, true); err != nil {
+			return errors.Wrap(err, "unable to register NAT ID flag on VPC NAT rule remove")
+		}
+
+		if err := flag.AddNATRule(self, _RuleCfg); err != nil {
+			return errors.Wrap(err, "unable to register rule flags on VPC NAT rule remove")
+		}
+
+		return nil
+	},
+}
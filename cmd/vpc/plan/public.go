@@ -0,0 +1,89 @@
+package plan
+
+import (
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/joyent/freebsd-vpc/internal/topology"
+	"github.com/pkg/errors"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName = "plan"
+	_KeyFile = config.KeyPlanFile
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "show the changes needed to apply a VPC topology file",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The plan operation of vpc(8) compares the VPC topology described by a
+topology file with the VPC objects in the kernel and displays the actions
+"vpc apply" would run, without changing anything.`,
+		Example: `% vpc plan -f host.hcl
+ ACTION  KIND     NAME    ID                                    DETAIL
+ create  switch   vpcsw0  da64c3f3-095d-91e5-df01-5aabcfc52468  vni 123, mac 5a:ab:cf:c5:24:68
+ create  vmnic    vm0     07f95a11-6788-2ae7-c306-ba95cff1db38  mac ba:95:cf:f1:db:38
+ create  port     vm0     fd436f9c-1f77-11e8-8002-0cc47a6c7d1e  switch vpcsw0, vni 123
+ modify  port     vm0     fd436f9c-1f77-11e8-8002-0cc47a6c7d1e  connect vm0
+
+   TOTAL  4`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			renderer, err := command.NewRenderer(cons)
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			topo, err := topology.Load(viper.GetString(_KeyFile))
+			if err != nil {
+				return errors.Wrap(err, "unable to load VPC topology")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			plan, err := topology.NewPlan(topo, snap)
+			if err != nil {
+				return errors.Wrap(err, "unable to plan VPC topology changes")
+			}
+
+			if plan.Empty() && !renderer.Structured() {
+				cons.Write([]byte("No changes: the VPC objects match the topology file.\n"))
+				return nil
+			}
+
+			return renderer.Render(plan.Table())
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddTopologyFile(self, _KeyFile); err != nil {
+			return errors.Wrap(err, "unable to register file flag on VPC plan")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,382 @@
+package prune
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/joyent/freebsd-vpc/internal/prune"
+	"github.com/mattn/go-isatty"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName         = "prune"
+	_KeyDryRun       = config.KeyPruneDryRun
+	_KeyLedger       = config.KeyPruneLedger
+	_KeyMinAge       = config.KeyPruneMinAge
+	_KeyObjType      = config.KeyPruneObjType
+	_KeyRetry        = config.KeyPruneRetry
+	_KeyRetryTimeout = config.KeyPruneRetryTimeout
+	_KeyYes          = config.KeyPruneYes
+)
+
+// _PruneObjTypes are the object types that can be orphans, in the order they
+// are destroyed.
+var _PruneObjTypes = []vpc.ObjType{
+	vpc.ObjTypeSwitchPort,
+	vpc.ObjTypeLinkEth,
+	vpc.ObjTypeNICVM,
+	vpc.ObjTypeSwitch,
+}
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "destroy orphaned VPC objects",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The prune operation of vpc(8) destroys the VPC objects left behind by failed
+setup or teardown scripts:
+
+  - switch ports with nothing connected
+  - ethlinks that are not connected to a port
+  - vmnics that are not connected to a port
+  - switches without ports
+
+Orphans are destroyed in that order.  Ports attached to a VPC NAT or a VPC Mux,
+and ethlinks used by a VPC NAT or a VPC Mux, are in use and never pruned.  A
+switch whose ports are all orphans is pruned by the next run.  Switches and
+switch ports are only pruned if the kernel can list the ports of every switch.
+
+The kernel does not record when VPC objects are created: the age used by
+--min-age is the time elapsed since prune first found the object orphaned, as
+recorded in the ledger file.  Dry runs update the ledger too, so running
+"vpc prune --dry-run" periodically ages orphans without destroying anything.
+
+Prune asks for confirmation before destroying anything unless --yes is given.`,
+		Example: `% doas vpc prune --dry-run
+% doas vpc prune --obj-type=vpcp,vmnic
+% doas vpc prune --min-age=1h --yes`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			renderer, err := command.NewRenderer(cons)
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			objTypes, err := selectObjTypes()
+			if err != nil {
+				return err
+			}
+
+			minAge := viper.GetDuration(_KeyMinAge)
+			ledgerPath := viper.GetString(_KeyLedger)
+			switch {
+			case minAge < 0:
+				return errors.Errorf("minimum age %s can not be negative", minAge)
+			case minAge > 0 && ledgerPath == "":
+				return errors.New("a ledger file is required to prune by age")
+			}
+
+			retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+			if err != nil {
+				return errors.Wrap(err, "unable to parse retry flags")
+			}
+			vpc.SetRetryPolicy(retryPolicy)
+
+			ledger, err := prune.LoadLedger(ledgerPath)
+			if err != nil {
+				return errors.Wrap(err, "unable to load orphan ledger")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			if err := prune.Check(snap, objTypes); err != nil {
+				return errors.Errorf("%v: use --obj-type=ethlink,vmnic to prune the other VPC objects", err)
+			}
+
+			now := snap.Taken
+			candidates := ledger.Observe(snap.Orphans(), now)
+
+			selected := prune.Select(candidates, objTypes, minAge, now)
+
+			saveLedger := func() {
+				if err := ledger.Save(); err != nil {
+					log.Warn().Err(err).Msg("unable to save orphan ledger")
+				}
+			}
+
+			if len(selected) == 0 {
+				saveLedger()
+				if renderer.Structured() {
+					return renderer.Render(orphanTable(selected, now))
+				}
+
+				cons.Write([]byte(fmt.Sprintf("No orphaned VPC objects to prune (%d orphans found in total).\n", len(candidates))))
+				return nil
+			}
+
+			if err := renderer.Render(orphanTable(selected, now)); err != nil {
+				return err
+			}
+
+			if viper.GetBool(_KeyDryRun) {
+				saveLedger()
+				if !renderer.Structured() {
+					cons.Write([]byte(fmt.Sprintf("Dry run: %d orphaned VPC objects would be pruned.\n", len(selected))))
+				}
+				return nil
+			}
+
+			if !viper.GetBool(_KeyYes) {
+				confirmed, err := confirm(fmt.Sprintf("Prune %d orphaned VPC objects? [y/N] ", len(selected)))
+				if err != nil {
+					return err
+				}
+
+				if !confirmed {
+					saveLedger()
+					fmt.Fprintln(os.Stderr, "Aborted.")
+					return nil
+				}
+			}
+
+			var pruned, failed int
+			for _, c := range selected {
+				if err := prune.Destroy(c.Orphan, snap); err != nil {
+					log.Error().Err(err).
+						Object("id", c.ID).
+						Str("unit-name", c.UnitName).
+						Msg("unable to prune VPC object")
+					failed++
+					continue
+				}
+
+				log.Info().
+					Object("id", c.ID).
+					Str("unit-name", c.UnitName).
+					Str("reason", c.Reason).
+					Msg("pruned VPC object")
+				ledger.Forget(c.ID)
+				pruned++
+			}
+			saveLedger()
+
+			if !renderer.Structured() {
+				cons.Write([]byte(fmt.Sprintf("Pruned %d orphaned VPC objects.\n", pruned)))
+			}
+
+			if failed > 0 {
+				return errors.Errorf("unable to prune %d of %d orphaned VPC objects", failed, len(selected))
+			}
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		{
+			const (
+				key          = _KeyDryRun
+				longName     = "dry-run"
+				shortName    = "n"
+				defaultValue = false
+				description  = "Display the orphaned VPC objects without destroying them"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = _KeyLedger
+				longName     = "ledger"
+				shortName    = ""
+				defaultValue = "/var/db/vpc/orphans.json"
+				description  = "Ledger file recording when orphans were first seen (empty disables it)"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = _KeyMinAge
+				longName     = "min-age"
+				shortName    = ""
+				defaultValue = time.Duration(0)
+				description  = "Only prune objects that have been orphaned for at least this long"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.DurationP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key       = _KeyObjType
+				longName  = "obj-type"
+				shortName = "t"
+			)
+			defaultValue := []string{"all"}
+
+			objTypesStrs := make([]string, len(_PruneObjTypes))
+			for i := range _PruneObjTypes {
+				objTypesStrs[i] = _PruneObjTypes[i].String()
+			}
+			description := fmt.Sprintf("Only prune objects of the given types. Valid types: %s", strings.Join(objTypesStrs, ", "))
+
+			flags := self.Cobra.Flags()
+			flags.StringSliceP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = _KeyYes
+				longName     = "yes"
+				shortName    = "y"
+				defaultValue = false
+				description  = "Prune without asking for confirmation"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC prune")
+		}
+
+		return nil
+	},
+}
+
+// selectObjTypes returns the set of object types selected with --obj-type.
+func selectObjTypes() (map[vpc.ObjType]bool, error) {
+	selected := make(map[vpc.ObjType]bool, len(_PruneObjTypes))
+	for _, name := range viper.GetStringSlice(_KeyObjType) {
+		for _, objTypeStr := range strings.Split(name, ",") {
+			objTypeStr = strings.TrimSpace(strings.ToLower(objTypeStr))
+			if objTypeStr == "all" {
+				for _, objType := range _PruneObjTypes {
+					selected[objType] = true
+				}
+				continue
+			}
+
+			var found bool
+			for _, objType := range _PruneObjTypes {
+				if objTypeStr == objType.String() {
+					selected[objType] = true
+					found = true
+				}
+			}
+
+			if !found {
+				return nil, errors.Errorf("unsupported VPC Object Type %q for prune", objTypeStr)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// confirm asks the user a yes/no question on the terminal.  The answer
+// defaults to no, and confirm fails when stdin is not a terminal.
+func confirm(prompt string) (bool, error) {
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return false, errors.New("refusing to prune without confirmation: stdin is not a terminal, use --yes")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, errors.Wrap(err, "unable to read confirmation")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+var orphanColumns = []command.Column{
+	{Name: "type", Align: tablewriter.ALIGN_LEFT},
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "id", Align: tablewriter.ALIGN_LEFT},
+	{Name: "reason", Align: tablewriter.ALIGN_LEFT},
+	{Name: "age", Align: tablewriter.ALIGN_RIGHT},
+}
+
+type orphanRecord struct {
+	prune.Candidate
+	age time.Duration
+}
+
+func (r orphanRecord) Values() []interface{} {
+	return []interface{}{r.ObjType, r.UnitName, r.ID, r.Reason, r.age}
+}
+
+func orphanTable(candidates []prune.Candidate, now time.Time) command.Table {
+	records := make([]command.Record, len(candidates))
+	for i, c := range candidates {
+		records[i] = orphanRecord{Candidate: c, age: c.Age(now).Truncate(time.Second)}
+	}
+
+	return command.Table{
+		Name:    "orphans",
+		Columns: orphanColumns,
+		Records: records,
+		Total:   true,
+	}
+}
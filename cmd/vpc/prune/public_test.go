@@ -0,0 +1,74 @@
+package prune
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/spf13/viper"
+)
+
+func TestSelectObjTypes(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		objTypes []string
+		want     map[vpc.ObjType]bool
+		errStr   string
+	}{
+		{
+			objTypes: []string{"all"},
+			want: map[vpc.ObjType]bool{
+				vpc.ObjTypeSwitchPort: true,
+				vpc.ObjTypeLinkEth:    true,
+				vpc.ObjTypeNICVM:      true,
+				vpc.ObjTypeSwitch:     true,
+			},
+		},
+		{
+			objTypes: []string{"vpcp,vmnic"},
+			want: map[vpc.ObjType]bool{
+				vpc.ObjTypeSwitchPort: true,
+				vpc.ObjTypeNICVM:      true,
+			},
+		},
+		{
+			objTypes: []string{" VPCSW ", "ethlink"},
+			want: map[vpc.ObjType]bool{
+				vpc.ObjTypeSwitch:  true,
+				vpc.ObjTypeLinkEth: true,
+			},
+		},
+		{
+			objTypes: []string{"vpcp,vpcnat"},
+			errStr:   `unsupported VPC Object Type "vpcnat" for prune`,
+		},
+		{
+			objTypes: []string{"bogus"},
+			errStr:   `unsupported VPC Object Type "bogus" for prune`,
+		},
+	}
+
+	for _, test := range tests {
+		viper.Set(config.KeyPruneObjType, test.objTypes)
+
+		got, err := selectObjTypes()
+		if test.errStr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.errStr) {
+				t.Errorf("%q: expected an error containing %q, got %v", test.objTypes, test.errStr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.objTypes, err)
+			continue
+		}
+
+		if diff := pretty.Compare(got, test.want); diff != "" {
+			t.Errorf("%q: object types diff: (-got +want)\n%s", test.objTypes, diff)
+		}
+	}
+}
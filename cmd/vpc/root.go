@@ -1,17 +1,29 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path"
+	"strings"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	gopsagent "github.com/google/gops/agent"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/agent"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/apply"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/db"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/destroy"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/doc"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/ethlink"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/id"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/intf"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/list"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/mux"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/nat"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/plan"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/prune"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/shell"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/state"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/version"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vm"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vmnic"
@@ -32,13 +44,22 @@ import (
 const cmdName = "root"
 
 var subCommands = command.Commands{
+	apply.Cmd,
 	db.Cmd,
+	destroy.Cmd,
 	doc.Cmd,
 	ethlink.Cmd,
+	id.Cmd,
 	intf.Cmd,
 	list.Cmd,
 	agent.Cmd,
+	mux.Cmd,
+	nat.Cmd,
+	plan.Cmd,
+	prune.Cmd,
+	router.Cmd,
 	shell.Cmd,
+	state.Cmd,
 	version.Cmd,
 	vm.Cmd,
 	vmnic.Cmd,
@@ -172,6 +193,55 @@ $ vpc list
 			viper.SetDefault(key, defaultValue)
 		}
 
+		{
+			const (
+				key          = config.KeyOutputFormat
+				longName     = "output"
+				shortName    = "o"
+				defaultValue = string(command.OutputTable)
+			)
+			formats := make([]string, len(command.OutputFormats))
+			for i, f := range command.OutputFormats {
+				formats[i] = string(f)
+			}
+			description := fmt.Sprintf("Output format: %s", strings.Join(formats, ", "))
+
+			flags := self.Cobra.PersistentFlags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeyOutputNoHeaders
+				longName     = "no-headers"
+				shortName    = ""
+				defaultValue = false
+				description  = "Omit table headers and footers"
+			)
+
+			flags := self.Cobra.PersistentFlags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key         = config.KeyOutputColumns
+				longName    = "columns"
+				shortName   = ""
+				description = "Comma separated list of the columns to output"
+			)
+			var defaultValue []string
+
+			flags := self.Cobra.PersistentFlags()
+			flags.StringSliceP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
 		return nil
 	},
 }
@@ -187,6 +257,9 @@ func Execute() error {
 		return err
 	}
 
+	// Every vpc_ctl(2) request is traced at debug level.
+	vpc.SetInterceptors(vpc.TraceInterceptor(log.Logger))
+
 	if !viper.GetBool(config.KeyUseGoogleAgent) {
 		log.Debug().Msg("gops(1) agent disabled by request")
 	} else {
@@ -0,0 +1,75 @@
+package create
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcrtr"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName     = "create"
+	_KeyRouterID = config.KeyRouterCreateRouterID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "create a VPC router",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Creating VPC Router...")))
+
+			id, err := flag.GetID(viper.GetViper(), _KeyRouterID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC ID")
+			}
+
+			routerCfg := vpcrtr.Config{
+				ID: id,
+			}
+
+			vpcRouter, err := vpcrtr.Create(routerCfg)
+			if err != nil {
+				log.Error().Err(err).Str("id", id.String()).Msg("vpcrtr create failed")
+				return errors.Wrap(err, "unable to create VPC Router")
+			}
+			defer vpcRouter.Close()
+
+			if err := vpcRouter.Commit(); err != nil {
+				log.Error().Err(err).Str("id", id.String()).Msg("vpcrtr commit failed")
+				return errors.Wrap(err, "unable to commit VPC Router")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Str("id", id.String()).Msg("vpcrtr created")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddRouterID(self, _KeyRouterID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Router create")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,91 @@
+package destroy
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcrtr"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName         = "destroy"
+	_KeyRouterID     = config.KeyRouterDestroyRouterID
+	_KeyRetry        = config.KeyRouterDestroyRetry
+	_KeyRetryTimeout = config.KeyRouterDestroyRetryTimeout
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+	Cobra: &cobra.Command{
+		Use:              _CmdName,
+		Aliases:          []string{"rm", "del", "delete"},
+		TraverseChildren: true,
+		Short:            "destroy a VPC router",
+		SilenceUsage:     true,
+		Args:             cobra.NoArgs,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: runE,
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddRouterID(self, _KeyRouterID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Router destroy")
+		}
+
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC Router destroy")
+		}
+
+		return nil
+	},
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	cons := conswriter.GetTerminal()
+
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
+	cons.Write([]byte(fmt.Sprintf("Destroying VPC Router...")))
+
+	id, err := flag.GetID(viper.GetViper(), _KeyRouterID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get VPC ID")
+	}
+
+	routerCfg := vpcrtr.Config{
+		ID:        id,
+		Writeable: true,
+	}
+
+	log.Info().Object("cfg", routerCfg).Str("op", "destroy").Msg("vpc_ctl")
+
+	vpcRouter, err := vpcrtr.Open(routerCfg)
+	if err != nil {
+		return errors.Wrap(err, "unable to open VPC Router")
+	}
+	defer vpcRouter.Close()
+
+	if err := vpcRouter.Destroy(); err != nil {
+		return errors.Wrap(err, "unable to destroy VPC Router")
+	}
+
+	cons.Write([]byte("done.\n"))
+
+	return nil
+}
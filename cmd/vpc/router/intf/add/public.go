@@ -0,0 +1,109 @@
+package add
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcrtr"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName     = "add"
+	_KeyAddr     = config.KeyRouterIntfAddAddr
+	_KeyPortID   = config.KeyRouterIntfAddPortID
+	_KeyRouterID = config.KeyRouterIntfAddRouterID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "attach a VPC Switch Port to a VPC Router",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Adding VPC Router interface...")))
+
+			routerID, err := flag.GetID(viper.GetViper(), _KeyRouterID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Router ID")
+			}
+
+			portID, err := flag.GetPortID(viper.GetViper(), _KeyPortID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get switch port ID")
+			}
+
+			addr, err := flag.GetIPNet(viper.GetViper(), _KeyAddr)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Router interface address")
+			}
+
+			routerCfg := vpcrtr.Config{
+				ID:        routerID,
+				Writeable: true,
+			}
+
+			vpcRouter, err := vpcrtr.Open(routerCfg)
+			if err != nil {
+				log.Error().Err(err).Object("router-id", routerID).Msg("VPC Router open failed")
+				return errors.Wrap(err, "unable to open VPC Router")
+			}
+			defer vpcRouter.Close()
+
+			if err := vpcRouter.InterfaceAdd(portID, addr); err != nil {
+				log.Error().Err(err).Object("router-id", routerID).Object("port-id", portID).Str("addr", addr.String()).Msg("vpc router interface add failed")
+				return errors.Wrap(err, "unable to add VPC Router interface")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Object("router-id", routerID).Object("port-id", portID).Str("addr", addr.String()).Msg("VPC Switch Port attached to VPC Router")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddRouterID(self, _KeyRouterID, true); err != nil {
+			return errors.Wrap(err, "unable to register Router ID flag on VPC Router interface add")
+		}
+
+		if err := flag.AddPortID(self, _KeyPortID, true); err != nil {
+			return errors.Wrap(err, "unable to register Port ID flag on VPC Router interface add")
+		}
+
+		{
+			const (
+				key          = _KeyAddr
+				longName     = "addr"
+				shortName    = "a"
+				defaultValue = ""
+				description  = "address and prefix length of the VPC Router interface (e.g. 10.0.0.1/24)"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			self.Cobra.MarkFlagRequired(longName)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
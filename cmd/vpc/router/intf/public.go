@@ -0,0 +1,32 @@
+package intf
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/intf/add"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const _CmdName = "interface"
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:     _CmdName,
+		Aliases: []string{"int", "intf"},
+		Short:   "VPC router interface management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			add.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", _CmdName)
+		}
+
+		return nil
+	},
+}
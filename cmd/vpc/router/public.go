@@ -0,0 +1,40 @@
+package router
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/create"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/destroy"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/intf"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/list"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/route"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const _CmdName = "router"
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:     _CmdName,
+		Aliases: []string{"rtr"},
+		Short:   "VPC router management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			create.Cmd,
+			destroy.Cmd,
+			intf.Cmd,
+			list.Cmd,
+			route.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", _CmdName)
+		}
+
+		return nil
+	},
+}
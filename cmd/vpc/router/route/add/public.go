@@ -0,0 +1,109 @@
+package add
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcrtr"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName        = "add"
+	_KeyDestination = config.KeyRouterRouteAddDestination
+	_KeyPortID      = config.KeyRouterRouteAddPortID
+	_KeyRouterID    = config.KeyRouterRouteAddRouterID
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "add a route to a VPC Router",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cons := conswriter.GetTerminal()
+
+			cons.Write([]byte(fmt.Sprintf("Adding VPC Router route...")))
+
+			routerID, err := flag.GetID(viper.GetViper(), _KeyRouterID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Router ID")
+			}
+
+			portID, err := flag.GetPortID(viper.GetViper(), _KeyPortID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get switch port ID")
+			}
+
+			dst, err := flag.GetIPNet(viper.GetViper(), _KeyDestination)
+			if err != nil {
+				return errors.Wrap(err, "unable to get route destination")
+			}
+
+			routerCfg := vpcrtr.Config{
+				ID:        routerID,
+				Writeable: true,
+			}
+
+			vpcRouter, err := vpcrtr.Open(routerCfg)
+			if err != nil {
+				log.Error().Err(err).Object("router-id", routerID).Msg("VPC Router open failed")
+				return errors.Wrap(err, "unable to open VPC Router")
+			}
+			defer vpcRouter.Close()
+
+			if err := vpcRouter.RouteAdd(dst, portID); err != nil {
+				log.Error().Err(err).Object("router-id", routerID).Object("port-id", portID).Str("destination", dst.String()).Msg("vpc router route add failed")
+				return errors.Wrap(err, "unable to add VPC Router route")
+			}
+
+			cons.Write([]byte("done.\n"))
+
+			log.Info().Object("router-id", routerID).Object("port-id", portID).Str("destination", dst.String()).Msg("VPC Router route added")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddRouterID(self, _KeyRouterID, true); err != nil {
+			return errors.Wrap(err, "unable to register Router ID flag on VPC Router route add")
+		}
+
+		if err := flag.AddPortID(self, _KeyPortID, true); err != nil {
+			return errors.Wrap(err, "unable to register Port ID flag on VPC Router route add")
+		}
+
+		{
+			const (
+				key          = _KeyDestination
+				longName     = "destination"
+				shortName    = "d"
+				defaultValue = ""
+				description  = "destination prefix of the route (e.g. 10.1.0.0/16)"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			self.Cobra.MarkFlagRequired(longName)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/router/route/add"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const _CmdName = "route"
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:   _CmdName,
+		Short: "VPC router route management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			add.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", _CmdName)
+		}
+
+		return nil
+	},
+}
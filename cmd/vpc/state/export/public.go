@@ -0,0 +1,102 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/joyent/freebsd-vpc/internal/topology"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName = "export"
+	_KeyFile = config.KeyStateExportFile
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "write the VPC objects in the kernel to a state file",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The export operation of vpc(8) writes the switches, ports, port connections,
+uplinks, ethlinks, and VM NIC settings (MAC, MTU, and hardware queues) found in
+the kernel to a versioned state file that "vpc state restore" recreates after a
+reboot.  The state file is a topology file in which objects are named after
+their unit name.
+
+VPC Routers, NATs, and Muxes, and the port connections to them, can not be
+exported: they are skipped with a warning.`,
+		Example: `% doas vpc state export -f /var/db/vpc/state.json`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			path := viper.GetString(_KeyFile)
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			state, warnings, err := topology.Export(snap)
+			if err != nil {
+				return errors.Wrap(err, "unable to export VPC state")
+			}
+
+			for _, warning := range warnings {
+				log.Warn().Msg(warning)
+			}
+
+			if err := state.Write(path); err != nil {
+				return errors.Wrap(err, "unable to write VPC state")
+			}
+
+			var ports int
+			for _, sw := range state.Switches {
+				ports += len(sw.Ports)
+			}
+
+			log.Info().
+				Str("file", path).
+				Int("switches", len(state.Switches)).
+				Int("ports", ports).
+				Int("vmnics", len(state.VMNICs)).
+				Int("ethlinks", len(state.EthLinks)).
+				Int("skipped", len(warnings)).
+				Msg("VPC state exported")
+
+			cons.Write([]byte(fmt.Sprintf("Exported %d switches, %d ports, %d vmnics, and %d ethlinks to %q.\n",
+				len(state.Switches), ports, len(state.VMNICs), len(state.EthLinks), path)))
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddStateFile(self, _KeyFile); err != nil {
+			return errors.Wrap(err, "unable to register file flag on VPC state export")
+		}
+
+		return nil
+	},
+}
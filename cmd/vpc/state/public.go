@@ -0,0 +1,33 @@
+package state
+
+import (
+	"github.com/joyent/freebsd-vpc/cmd/vpc/state/export"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/state/restore"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const cmdName = "state"
+
+var Cmd = &command.Command{
+	Name: cmdName,
+
+	Cobra: &cobra.Command{
+		Use:   cmdName,
+		Short: "VPC kernel state management",
+	},
+
+	Setup: func(self *command.Command) error {
+		subCommands := command.Commands{
+			export.Cmd,
+			restore.Cmd,
+		}
+
+		if err := self.Register(subCommands); err != nil {
+			return errors.Wrapf(err, "unable to register sub-commands under %s", cmdName)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,161 @@
+package restore
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/joyent/freebsd-vpc/internal/topology"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName         = "restore"
+	_KeyFile         = config.KeyStateRestoreFile
+	_KeySkipExisting = config.KeyStateRestoreSkipExisting
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "recreate the VPC objects described by a state file",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The restore operation of vpc(8) recreates the VPC objects of a state file
+written by "vpc state export", with the same IDs.  Restore is idempotent: like
+"vpc apply", it only runs the actions needed to converge the kernel onto the
+state file.
+
+With --skip-existing, objects that already exist are left untouched and only
+missing objects are created.  Differences between the existing objects and the
+state file are reported as drift and logged as warnings, but are not an error.
+This is the mode to use from rc.d(8).`,
+		Example: `% doas vpc state restore -f /var/db/vpc/state.json
+% doas vpc state restore --skip-existing -f /var/db/vpc/state.json`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			renderer, err := command.NewRenderer(cons)
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			state, err := topology.LoadState(viper.GetString(_KeyFile))
+			if err != nil {
+				return errors.Wrap(err, "unable to load VPC state")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			plan, err := topology.NewPlan(&state.Topology, snap)
+			if err != nil {
+				return errors.Wrap(err, "unable to plan VPC state restore")
+			}
+
+			var drift *topology.Plan
+			if viper.GetBool(_KeySkipExisting) {
+				drift = &topology.Plan{Actions: plan.SkipExisting()}
+				for _, act := range drift.Actions {
+					log.Warn().Object("action", act).Msg("VPC state drift, skipping")
+				}
+			}
+
+			if renderer.Structured() {
+				tables := []command.Table{plan.Table()}
+				if drift != nil {
+					driftTable := drift.Table()
+					driftTable.Name = "drift"
+					tables = append(tables, driftTable)
+				}
+
+				if err := renderer.Render(tables...); err != nil {
+					return err
+				}
+			} else {
+				if drift != nil && !drift.Empty() {
+					cons.Write([]byte(fmt.Sprintf("Skipping %d actions on existing objects that drifted from the state file:\n", len(drift.Actions))))
+					if err := renderer.Render(drift.Table()); err != nil {
+						return err
+					}
+				}
+
+				if plan.Empty() {
+					cons.Write([]byte("No objects to restore: every object of the state file exists.\n"))
+					return nil
+				}
+
+				if err := renderer.Render(plan.Table()); err != nil {
+					return err
+				}
+
+				cons.Write([]byte(fmt.Sprintf("Restoring %d actions...", len(plan.Actions))))
+			}
+
+			if plan.Empty() {
+				return nil
+			}
+
+			err = plan.Apply(func(act topology.Action) {
+				log.Debug().Object("action", act).Msg("restoring")
+			})
+			if err != nil {
+				log.Error().Err(err).Msg("VPC state restore failed")
+				return errors.Wrap(err, "unable to restore VPC state")
+			}
+
+			if !renderer.Structured() {
+				cons.Write([]byte("done.\n"))
+			}
+
+			log.Info().Int("actions", len(plan.Actions)).Msg("VPC state restored")
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddStateFile(self, _KeyFile); err != nil {
+			return errors.Wrap(err, "unable to register file flag on VPC state restore")
+		}
+
+		{
+			const (
+				key          = _KeySkipExisting
+				longName     = "skip-existing"
+				shortName    = ""
+				defaultValue = false
+				description  = "Only create missing objects and report drift of existing objects"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
@@ -2,14 +2,24 @@ package version
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/joyent/freebsd-vpc/internal/buildtime"
 	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
-const cmdName = "version"
+const (
+	cmdName   = "version"
+	keyKernel = config.KeyVersionKernel
+)
 
 var Cmd = &command.Command{
 	Name: cmdName,
@@ -46,11 +56,74 @@ var Cmd = &command.Command{
 
 			fmt.Printf("Build Date: %s\n", buildtime.BuildDate)
 
+			if viper.GetBool(keyKernel) {
+				return printKernelABI()
+			}
+
 			return nil
 		},
 	},
 
 	Setup: func(self *command.Command) error {
+		{
+			const (
+				key          = keyKernel
+				longName     = "kernel"
+				shortName    = "k"
+				defaultValue = false
+				description  = "print the VPC ABI version negotiated with the kernel for each VPC type"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
 		return nil
 	},
 }
+
+// printKernelABI prints the ABI version negotiated for each VPC type along with
+// the versions supported by this binary.
+func printKernelABI() error {
+	objTypes := vpc.ObjTypes()
+	sort.SliceStable(objTypes, func(i, j int) bool { return objTypes[i].String() < objTypes[j].String() })
+
+	fmt.Printf("Kernel ABI:\n")
+	for _, objType := range objTypes {
+		negotiated := "none"
+		ver, err := vpc.NegotiateVersion(objType)
+		switch {
+		case err == nil:
+			negotiated = "v" + strconv.FormatUint(uint64(ver), 10)
+		case !vpc.IsUnsupported(err):
+			return errors.Wrapf(err, "unable to negotiate ABI version for %s", objType)
+		}
+
+		log.Debug().
+			Str("obj-type", objType.String()).
+			Str("negotiated", negotiated).
+			Str("library", formatVersions(vpc.Versions(objType))).
+			Msg("abi")
+
+		fmt.Printf("  %-8s %-4s (library: %s)\n", objType.String()+":", negotiated,
+			formatVersions(vpc.Versions(objType)))
+	}
+
+	return nil
+}
+
+func formatVersions(vers []vpc.HandleVersion) string {
+	if len(vers) == 0 {
+		return "none"
+	}
+
+	strs := make([]string, len(vers))
+	for i, ver := range vers {
+		strs[i] = strconv.FormatUint(uint64(ver), 10)
+	}
+
+	return strings.Join(strs, ",")
+}
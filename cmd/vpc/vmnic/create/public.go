@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vmnic"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpctest"
 	"github.com/joyent/freebsd-vpc/internal/command"
@@ -60,19 +61,21 @@ var Cmd = &command.Command{
 				MAC: mac,
 			}
 
+			// The VM NIC is only committed once it has been found on the host,
+			// otherwise it is destroyed when the transaction rolls back.
+			txn := vpc.NewTxn()
+			defer func() {
+				if err := txn.Rollback(); err != nil {
+					log.Error().Err(err).Msg("failure during undo")
+				}
+			}()
+
 			vmNIC, err := vmnic.Create(vmnicCfg)
 			if err != nil {
 				log.Error().Err(err).Object("vmnic-id", id).Msg("vmnic create failed")
 				return errors.Wrap(err, "unable to create VM NIC")
 			}
-			defer vmNIC.Close()
-
-			if err := vmNIC.Commit(); err != nil {
-				log.Error().Err(err).Object("vmnic-id", id).Msg("VM NIC commit failed")
-				return errors.Wrap(err, "unable to commit VM NIC")
-			}
-
-			cons.Write([]byte("done.\n"))
+			txn.Created("VM NIC", vmNIC)
 
 			var newVMNIC net.Interface
 			{ // Get the before/after
@@ -82,13 +85,19 @@ var Cmd = &command.Command{
 				}
 				_, newIfaces, _ := existingIfaces.Difference(ifacesAfterCreate)
 
-				var newVMNICMAC net.HardwareAddr = id.Node[:]
-				newVMNIC, err = newIfaces.FindMAC(newVMNICMAC)
+				newVMNIC, err = newIfaces.FindMAC(mac)
 				if err != nil {
-					return errors.Wrapf(err, "unable to find new VM NIC with MAC %q", id.Node)
+					return errors.Wrapf(err, "unable to find new VM NIC with MAC %q", mac)
 				}
 			}
 
+			if err := txn.Commit(); err != nil {
+				log.Error().Err(err).Object("vmnic-id", id).Msg("VM NIC commit failed")
+				return errors.Wrap(err, "unable to commit VM NIC")
+			}
+
+			cons.Write([]byte("done.\n"))
+
 			log.Info().Object("vmnic-id", id).Str("mac", newVMNIC.HardwareAddr.String()).Str("name", newVMNIC.Name).Msg("VM NIC created")
 
 			return nil
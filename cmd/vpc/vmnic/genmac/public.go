@@ -6,6 +6,7 @@ import (
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
 	"github.com/sean-/conswriter"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +15,21 @@ const (
 	cmdName = "genmac"
 )
 
+var columns = []command.Column{
+	{Name: "id", Align: tablewriter.ALIGN_LEFT},
+	{Name: "mac", Align: tablewriter.ALIGN_RIGHT},
+}
+
+// genMACRecord is a generated VM NIC ID and its MAC address.
+type genMACRecord struct {
+	id  vpc.ID
+	mac net.HardwareAddr
+}
+
+func (r genMACRecord) Values() []interface{} {
+	return []interface{}{r.id, r.mac}
+}
+
 var Cmd = &command.Command{
 	Name: cmdName,
 
@@ -27,32 +43,18 @@ var Cmd = &command.Command{
 		},
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cons := conswriter.GetTerminal()
-
-			table := tablewriter.NewWriter(cons)
-			table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-			table.SetHeaderLine(false)
-			table.SetAutoFormatHeaders(true)
-
-			table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
-			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-			table.SetCenterSeparator("")
-			table.SetColumnSeparator("")
-			table.SetRowSeparator("")
-
-			table.SetHeader([]string{"id", "mac"})
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
 
 			id := vpc.GenID(vpc.ObjTypeNICVM)
 			var macAddr net.HardwareAddr = id.Node[:]
 
-			table.Append([]string{
-				id.String(),
-				macAddr.String(),
+			return renderer.Render(command.Table{
+				Columns: columns,
+				Records: []command.Record{genMACRecord{id: id, mac: macAddr}},
 			})
-
-			table.Render()
-
-			return nil
 		},
 	},
 
@@ -1,8 +1,7 @@
 package get
 
 import (
-	"strconv"
-
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vmnic"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/joyent/freebsd-vpc/internal/command/flag"
@@ -20,6 +19,13 @@ const (
 	keyVMNICID    = config.KeyVMNICGetVMNICID
 )
 
+// vmnicRecord holds the values of the VM NIC properties that were requested.
+type vmnicRecord []interface{}
+
+func (r vmnicRecord) Values() []interface{} {
+	return r
+}
+
 var Cmd = &command.Command{
 	Name: cmdName,
 
@@ -33,20 +39,10 @@ var Cmd = &command.Command{
 		},
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cons := conswriter.GetTerminal()
-
-			table := tablewriter.NewWriter(cons)
-			table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-			table.SetHeaderLine(false)
-			table.SetAutoFormatHeaders(true)
-
-			table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
-			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-			table.SetCenterSeparator("")
-			table.SetColumnSeparator("")
-			table.SetRowSeparator("")
-
-			table.SetHeader([]string{"id", "key", "value"})
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
 
 			id, err := flag.GetID(viper.GetViper(), keyVMNICID)
 			if err != nil {
@@ -54,7 +50,8 @@ var Cmd = &command.Command{
 			}
 
 			vmnicCfg := vmnic.Config{
-				ID: id,
+				ID:     id,
+				Rights: vpc.RightsReadOnly,
 			}
 			vmn, err := vmnic.Open(vmnicCfg)
 			if err != nil {
@@ -62,22 +59,23 @@ var Cmd = &command.Command{
 			}
 			defer vmn.Close()
 
+			cols := []command.Column{{Name: "id", Align: tablewriter.ALIGN_LEFT}}
+			rec := vmnicRecord{id}
+
 			if viper.GetBool(keyGetNQueues) {
 				numQueues, err := vmn.NQueuesGet()
 				if err != nil {
 					return errors.Wrapf(err, "unable to get the number of hardware queues")
 				}
 
-				table.Append([]string{
-					id.String(),
-					"num-queues",
-					strconv.FormatInt(int64(numQueues), 10),
-				})
+				cols = append(cols, command.Column{Name: "num-queues", Align: tablewriter.ALIGN_RIGHT})
+				rec = append(rec, numQueues)
 			}
 
-			table.Render()
-
-			return nil
+			return renderer.Render(command.Table{
+				Columns: cols,
+				Records: []command.Record{rec},
+			})
 		},
 	},
 
@@ -83,10 +83,9 @@ var Cmd = &command.Command{
 				}
 				_, newIfaces, _ := existingIfaces.Difference(ifacesAfterCreate)
 
-				var newSwitchMAC net.HardwareAddr = id.Node[:]
-				newSwitch, err = newIfaces.FindMAC(newSwitchMAC)
+				newSwitch, err = newIfaces.FindMAC(mac)
 				if err != nil {
-					return errors.Wrapf(err, "unable to find new VPC Switch with MAC %q", id.Node)
+					return errors.Wrapf(err, "unable to find new VPC Switch with MAC %q", mac)
 				}
 			}
 
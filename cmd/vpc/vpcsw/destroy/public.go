@@ -3,6 +3,7 @@ package destroy
 import (
 	"fmt"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/joyent/freebsd-vpc/internal/command/flag"
@@ -15,8 +16,10 @@ import (
 )
 
 const (
-	_CmdName     = "destroy"
-	_KeySwitchID = config.KeySWDestroySwitchID
+	_CmdName         = "destroy"
+	_KeySwitchID     = config.KeySWDestroySwitchID
+	_KeyRetry        = config.KeySWDestroyRetry
+	_KeyRetryTimeout = config.KeySWDestroyRetryTimeout
 )
 
 var Cmd = &command.Command{
@@ -41,6 +44,10 @@ var Cmd = &command.Command{
 			return errors.Wrap(err, "unable to register ID flag on VPC Switch destroy")
 		}
 
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC Switch destroy")
+		}
+
 		return nil
 	},
 }
@@ -48,6 +55,12 @@ var Cmd = &command.Command{
 func runE(cmd *cobra.Command, args []string) error {
 	cons := conswriter.GetTerminal()
 
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
 	cons.Write([]byte(fmt.Sprintf("Destroying VPC Switch...")))
 
 	id, err := flag.GetID(viper.GetViper(), _KeySwitchID)
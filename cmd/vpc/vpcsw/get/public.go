@@ -0,0 +1,153 @@
+package get
+
+import (
+	"net"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port/list"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName     = "get"
+	_KeySwitchID = config.KeySWGetSwitchID
+)
+
+var columns = []command.Column{
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "id", Align: tablewriter.ALIGN_LEFT},
+	{Name: "state", Align: tablewriter.ALIGN_LEFT},
+	{Name: "vni", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "ports", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "uplink", Align: tablewriter.ALIGN_LEFT},
+	{Name: "uplink-id", Align: tablewriter.ALIGN_LEFT, Wide: true},
+	{Name: "mac", Align: tablewriter.ALIGN_LEFT},
+	{Name: "mtu", Align: tablewriter.ALIGN_RIGHT},
+}
+
+// switchRecord is a VPC Switch in the output of get.  The uplink fields of a
+// switch without an uplink port are nil, as are the VNI and the number of
+// ports if the kernel can't list the ports of the switch.
+type switchRecord struct {
+	name     string
+	id       vpc.ID
+	state    string
+	vni      interface{}
+	ports    interface{}
+	uplink   interface{}
+	uplinkID interface{}
+	mac      net.HardwareAddr
+	mtu      uint32
+}
+
+func (r switchRecord) Values() []interface{} {
+	return []interface{}{r.name, r.id, r.state, r.vni, r.ports, r.uplink, r.uplinkID, r.mac, r.mtu}
+}
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "get VPC switch information",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The get operation of vpc(8) displays a VPC switch and its ports.  Each port
+is listed with its VNI, whether it is the uplink of the switch, and the unit
+name of the VM NIC, EthLink, or other VPC object connected to it, as displayed
+by "vpc switch port list".`,
+		Example: `% vpc switch get --switch-id=da64c3f3-095d-91e5-df01-5aabcfc52468
+ NAME    ID                                    STATE  VNI  PORTS  UPLINK  MAC                MTU
+ vpcsw0  da64c3f3-095d-91e5-df01-5aabcfc52468  up     123      2  vpcp1   5a:ab:cf:c5:24:68  1500
+ NAME   ID                                    VNI  UPLINK  PEER      PEER TYPE
+ vpcp0  7c9e6679-7425-40de-9402-e07fc1f90ae7  123  false   vmnic0    vmnic
+ vpcp1  ea58b648-203b-a707-cd02-7a552c8d5295  123  true    ethlink0  ethlink`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			id, err := flag.GetID(viper.GetViper(), _KeySwitchID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Switch ID")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			sw, found := snap.Switch(id)
+			if !found {
+				return errors.Errorf("VPC Switch %s not found", id)
+			}
+
+			rec := switchRecord{
+				name:  sw.UnitName,
+				id:    sw.ID,
+				state: "down",
+				mac:   sw.MAC,
+				mtu:   sw.MTU,
+			}
+
+			if sw.PortsKnown {
+				rec.vni = sw.VNI
+				rec.ports = len(sw.Ports)
+			}
+
+			if sw.Up {
+				rec.state = "up"
+			}
+
+			if sw.Uplink != (vpc.ID{}) {
+				rec.uplinkID = sw.Uplink
+				if uplink, found := snap.Port(sw.Uplink); found {
+					rec.uplink = uplink.UnitName
+				}
+			}
+
+			switchTable := command.Table{
+				Name:    "switch",
+				Columns: columns,
+				Records: []command.Record{rec},
+			}
+			portTable := list.PortTable(snap, sw)
+
+			// Only structured output reports an empty set of ports, and
+			// only if the ports are known.
+			if !sw.PortsKnown || len(portTable.Records) == 0 && !renderer.Structured() {
+				return renderer.Render(switchTable)
+			}
+
+			return renderer.Render(switchTable, portTable)
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddSwitchID(self, _KeySwitchID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Switch get")
+		}
+
+		return nil
+	},
+}
@@ -1,7 +1,7 @@
 package list
 
 import (
-	"strconv"
+	"net"
 	"strings"
 
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpctest"
@@ -16,6 +16,21 @@ const (
 	_CmdName = "list"
 )
 
+var columns = []command.Column{
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "index", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "mtu", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "mac", Align: tablewriter.ALIGN_LEFT},
+	{Name: "flags", Align: tablewriter.ALIGN_LEFT},
+}
+
+// ifaceRecord is a network interface in the output of list.
+type ifaceRecord net.Interface
+
+func (r ifaceRecord) Values() []interface{} {
+	return []interface{}{r.Name, r.Index, r.MTU, r.HardwareAddr, r.Flags}
+}
+
 var Cmd = &command.Command{
 	Name: _CmdName,
 
@@ -30,47 +45,30 @@ var Cmd = &command.Command{
 		},
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cons := conswriter.GetTerminal()
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
 
 			existingIfaces, err := vpctest.GetAllInterfaces()
 			if err != nil {
 				return errors.Wrapf(err, "unable to get all interfaces")
 			}
 
-			table := tablewriter.NewWriter(cons)
-			table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-			table.SetHeaderLine(false)
-			table.SetAutoFormatHeaders(true)
-
-			table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
-			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-			table.SetCenterSeparator("")
-			table.SetColumnSeparator("")
-			table.SetRowSeparator("")
-
-			table.SetHeader([]string{"name", "index", "mtu", "mac", "flags"})
-
-			var numInterfaces int64
+			records := make([]command.Record, 0)
 			for _, iface := range existingIfaces {
 				if !strings.HasPrefix(iface.Name, "vpcsw") {
 					continue
 				}
 
-				table.Append([]string{
-					iface.Name,
-					strconv.FormatInt(int64(iface.Index), 10),
-					strconv.FormatInt(int64(iface.MTU), 10),
-					iface.HardwareAddr.String(),
-					iface.Flags.String(),
-				})
-				numInterfaces++
+				records = append(records, ifaceRecord(iface))
 			}
 
-			table.SetFooter([]string{"total", strconv.FormatInt(numInterfaces, 10), "", "", ""})
-
-			table.Render()
-
-			return nil
+			return renderer.Render(command.Table{
+				Columns: columns,
+				Records: records,
+				Total:   true,
+			})
 		},
 	},
 
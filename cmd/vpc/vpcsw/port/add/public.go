@@ -99,28 +99,14 @@ var Cmd = &command.Command{
 
 			l2Name := viper.GetString(_KeyL2Name)
 
-			// Create a stack of commit and undo operations to walk through in the
-			// event of an error.
-			var commit bool
-			var commitFuncs, undoFuncs []func() error
+			// Every step is recorded in a transaction and unwound in reverse order
+			// unless the whole operation succeeds.
+			txn := vpc.NewTxn()
 			defer func() {
-				scopeHandlers := undoFuncs
-				modeStr := "undo"
-				if commit {
-					modeStr = "commit"
-					scopeHandlers = commitFuncs
-				}
-
-				for i := len(scopeHandlers) - 1; i >= 0; i-- {
-					if err := scopeHandlers[i](); err != nil {
-						log.Error().Err(err).Msgf("failure during %s", modeStr)
-					}
+				if err := txn.Rollback(); err != nil {
+					log.Error().Err(err).Msg("failure during undo")
 				}
 			}()
-			commitFuncs = append(commitFuncs, func() error {
-				cons.Write([]byte("done.\n"))
-				return nil
-			})
 
 			// 1) Open switch and add a port
 			switchCfg := vpcsw.Config{
@@ -133,26 +119,16 @@ var Cmd = &command.Command{
 				log.Error().Err(err).Object("switch-cfg", switchCfg).Msg("vpcsw open failed")
 				return errors.Wrap(err, "unable to open VPC Switch")
 			}
-			commitFuncs = append(commitFuncs, func() error {
-				if err := vpcSwitch.Close(); err != nil {
-					log.Error().Err(err).Msg("unable to commit VPC Switch")
-					return errors.Wrap(err, "unable to commit VPC switch during operation commit")
-				}
-
-				return nil
-			})
-			undoFuncs = append(undoFuncs, func() error {
-				if err := vpcSwitch.Close(); err != nil {
-					log.Error().Err(err).Msg("unable to clean up VPC Switch during error recovery")
-				}
-
-				return nil
-			})
+			txn.Opened("VPC Switch", vpcSwitch)
 
 			// If we have an EthLink, add it to the port
 			switch {
 			case l2Name == "":
-				if err = vpcSwitch.PortAdd(portID, portMAC); err != nil {
+				err = txn.Do("add VPC Switch Port",
+					func() error { return vpcSwitch.PortAdd(portID, portMAC) },
+					func() error { return vpcSwitch.PortRemove(portID) },
+				)
+				if err != nil {
 					log.Error().Err(err).
 						Object("port-id", portID).
 						Str("port-mac", portMAC.String()).
@@ -169,20 +145,19 @@ var Cmd = &command.Command{
 				if err != nil {
 					return errors.Wrap(err, "unable to create VPC EthLink")
 				}
+				txn.Created("VPC EthLink", el)
 
-				if err := el.Attach(); err != nil {
+				// Attaching is undone by destroying the uncommitted EthLink.
+				if err := txn.Do("attach VPC EthLink", el.Attach, nil); err != nil {
 					return errors.Wrapf(err, "unable to attach L2 link to device %q", l2Name)
 				}
-				commitFuncs = append(commitFuncs, func() error {
-					if err := el.Commit(); err != nil {
-						log.Error().Err(err).Object("ethlink", el).Msg("unable to commit VPC EthLink")
-						return errors.Wrap(err, "unable to commit VPC EthLink")
-					}
-					return nil
-				})
 
 				if viper.GetBool(_KeyUplink) {
-					if err := vpcSwitch.PortUplinkSet(portID, portMAC); err != nil {
+					err := txn.Do("set VPC Switch Port uplink",
+						func() error { return vpcSwitch.PortUplinkSet(portID, portMAC) },
+						func() error { return vpcSwitch.PortRemove(portID) },
+					)
+					if err != nil {
 						log.Error().Err(err).Object("port-id", portID).Object("switch-cfg", switchCfg).Msg("failed to set VPC Switch Port as an Uplink port")
 						return errors.Wrap(err, "unable to create a VPC Switch Port uplink")
 					}
@@ -197,8 +172,13 @@ var Cmd = &command.Command{
 					log.Error().Err(err).Object("port-id", portID).Object("switch-cfg", switchCfg).Msg("failed to connect VPC interface to VPC Switch Port")
 					return errors.Wrap(err, "unable to open VPC Switch Port")
 				}
+				txn.Opened("VPC Switch Port", vpcPort)
 
-				if err := vpcPort.Connect(ethLinkCfg.ID); err != nil {
+				err = txn.Do("connect VPC Switch Port",
+					func() error { return vpcPort.Connect(ethLinkCfg.ID) },
+					func() error { return vpcPort.Disconnect(ethLinkCfg.ID) },
+				)
+				if err != nil {
 					log.Error().Err(err).Object("ethlink-cfg", ethLinkCfg).Object("ethlink", el).Object("port-id", portID).Object("switch-cfg", switchCfg).Msg("failed to connect VPC interface to VPC Switch Port")
 					return errors.Wrap(err, "unable to connect VPC Interface to VPC Port")
 				}
@@ -206,7 +186,11 @@ var Cmd = &command.Command{
 				panic("invalid switch port add logic")
 			}
 
-			commit = true
+			if err := txn.Commit(); err != nil {
+				log.Error().Err(err).Msg("failure during commit")
+				return errors.Wrap(err, "unable to commit VPC Switch Port")
+			}
+			cons.Write([]byte("done.\n"))
 
 			// log.Info().Str("port-id", portAddCfg.ID.String()).Str("switch-id", switchID.String()).Str("uplink-id", uplinkID.String()). /*.Str("name", newPort.Name)*/ Msg("vpcp created")
 			log.Info().Object("port-id", portID).Str("switch-id", switchID.String()).Msg("vpcp created")
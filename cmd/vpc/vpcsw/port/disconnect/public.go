@@ -3,6 +3,7 @@ package disconnect
 import (
 	"fmt"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcp"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/joyent/freebsd-vpc/internal/command/flag"
@@ -15,9 +16,11 @@ import (
 )
 
 const (
-	_CmdName        = "disconnect"
-	_KeyPortID      = config.KeySWPortDisconnectPortID
-	_KeyInterfaceID = config.KeySWPortDisconnectInterfaceID
+	_CmdName         = "disconnect"
+	_KeyPortID       = config.KeySWPortDisconnectPortID
+	_KeyInterfaceID  = config.KeySWPortDisconnectInterfaceID
+	_KeyRetry        = config.KeySWPortDisconnectRetry
+	_KeyRetryTimeout = config.KeySWPortDisconnectRetryTimeout
 )
 
 var Cmd = &command.Command{
@@ -36,6 +39,12 @@ var Cmd = &command.Command{
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			cons := conswriter.GetTerminal()
 
+			retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+			if err != nil {
+				return errors.Wrap(err, "unable to get retry policy")
+			}
+			vpc.SetRetryPolicy(retryPolicy)
+
 			cons.Write([]byte(fmt.Sprintf("Disconnecting VPC Interface from VPC Switch Port...")))
 
 			interfaceID, err := flag.GetID(viper.GetViper(), _KeyInterfaceID)
@@ -82,6 +91,10 @@ var Cmd = &command.Command{
 			return errors.Wrap(err, "unable to register Port ID flag on VPC Switch Port disconnect")
 		}
 
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC Switch Port disconnect")
+		}
+
 		return nil
 	},
 }
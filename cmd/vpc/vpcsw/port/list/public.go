@@ -0,0 +1,199 @@
+package list
+
+import (
+	"net"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName     = "list"
+	_KeySwitchID = config.KeySWPortListSwitchID
+)
+
+var columns = []command.Column{
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "id", Align: tablewriter.ALIGN_LEFT},
+	{Name: "vni", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "uplink", Align: tablewriter.ALIGN_LEFT},
+	{Name: "peer", Align: tablewriter.ALIGN_LEFT},
+	{Name: "peer-type", Align: tablewriter.ALIGN_LEFT},
+	{Name: "peer-id", Align: tablewriter.ALIGN_LEFT, Wide: true},
+	{Name: "mac", Align: tablewriter.ALIGN_LEFT, Wide: true},
+	{Name: "mtu", Align: tablewriter.ALIGN_RIGHT, Wide: true},
+	{Name: "vlan", Align: tablewriter.ALIGN_RIGHT, Wide: true},
+}
+
+// portRecord is a VPC Switch Port in the output of list.  Fields of a port
+// without a peer are nil.
+type portRecord struct {
+	name     string
+	id       vpc.ID
+	vni      vpc.VNI
+	uplink   bool
+	peer     interface{}
+	peerType interface{}
+	peerID   interface{}
+	mac      net.HardwareAddr
+	mtu      uint32
+	vlan     vpc.VLAN
+}
+
+func (r portRecord) Values() []interface{} {
+	return []interface{}{r.name, r.id, r.vni, r.uplink, r.peer, r.peerType, r.peerID, r.mac, r.mtu, r.vlan}
+}
+
+// PortTable returns the ports of the switch sw found in snap.  Peers are
+// resolved to their unit name, e.g. vmnic3 or ethlink0, so that the path of a
+// VM can be traced through the switch.  The table is empty if the kernel can't
+// list the ports of sw.
+func PortTable(snap *mgmt.Snapshot, sw *mgmt.Switch) command.Table {
+	records := make([]command.Record, 0, len(sw.Ports))
+	for _, portID := range sw.Ports {
+		port, found := snap.Port(portID)
+		if !found {
+			continue
+		}
+
+		rec := portRecord{
+			name:   port.UnitName,
+			id:     port.ID,
+			vni:    port.VNI,
+			uplink: port.Uplink,
+			mac:    port.MAC,
+			mtu:    port.MTU,
+			vlan:   port.VLAN,
+		}
+
+		if peer, found := lookupPeer(snap, port); found {
+			rec.peer = peer.UnitName
+			rec.peerType = peer.ObjType
+			rec.peerID = peer.ID
+		} else if port.Peer != (vpc.ID{}) {
+			// The peer was destroyed while the snapshot was taken.
+			rec.peerID = port.Peer
+		}
+
+		records = append(records, rec)
+	}
+
+	return command.Table{
+		Name:    "ports",
+		Columns: columns,
+		Records: records,
+		Total:   true,
+	}
+}
+
+// lookupPeer returns the object connected to port.  VPC NATs and VPC Muxes
+// reference the ports they are attached to, rather than the other way around.
+func lookupPeer(snap *mgmt.Snapshot, port *mgmt.Port) (mgmt.Object, bool) {
+	if port.Peer != (vpc.ID{}) {
+		obj, _ := snap.Lookup(port.Peer)
+		switch peer := obj.(type) {
+		case *mgmt.VMNIC:
+			return peer.Object, true
+		case *mgmt.EthLink:
+			return peer.Object, true
+		case *mgmt.Router:
+			return peer.Object, true
+		case *mgmt.NAT:
+			return peer.Object, true
+		case *mgmt.Mux:
+			return peer.Object, true
+		}
+
+		return mgmt.Object{}, false
+	}
+
+	for _, nat := range snap.NATs {
+		if nat.Port == port.ID {
+			return nat.Object, true
+		}
+	}
+
+	for _, mux := range snap.Muxes {
+		for _, portID := range mux.Ports {
+			if portID == port.ID {
+				return mux.Object, true
+			}
+		}
+	}
+
+	return mgmt.Object{}, false
+}
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Aliases:      []string{"ls"},
+		Short:        "list the ports of a VPC switch",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		Long: `The list operation of vpc(8) displays the ports of a VPC switch: their VNI,
+whether the port is the uplink of the switch, and the VM NIC, EthLink, or other
+VPC object connected to the port.  Use --output=wide to display the IDs of the
+peers and the MAC address, MTU, and VLAN of the ports.`,
+		Example: `% vpc switch port list --switch-id=da64c3f3-095d-91e5-df01-5aabcfc52468
+ NAME   ID                                    VNI  UPLINK  PEER      PEER TYPE
+ vpcp0  7c9e6679-7425-40de-9402-e07fc1f90ae7  123  false   vmnic0    vmnic
+ vpcp1  ea58b648-203b-a707-cd02-7a552c8d5295  123  true    ethlink0  ethlink`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			renderer, err := command.NewRenderer(conswriter.GetTerminal())
+			if err != nil {
+				return errors.Wrap(err, "unable to configure output")
+			}
+
+			switchID, err := flag.GetSwitchID(viper.GetViper(), _KeySwitchID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Switch ID")
+			}
+
+			mgr, err := mgmt.New(nil)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Management handle")
+			}
+			defer mgr.Close()
+
+			snap, err := mgr.Snapshot()
+			if err != nil {
+				return errors.Wrap(err, "unable to snapshot VPC objects")
+			}
+
+			sw, found := snap.Switch(switchID)
+			if !found {
+				return errors.Errorf("VPC Switch %s not found", switchID)
+			}
+
+			if !sw.PortsKnown {
+				return errors.Errorf("unable to list the ports of VPC Switch %s: not supported by the kernel", switchID)
+			}
+
+			return renderer.Render(PortTable(snap, sw))
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddSwitchID(self, _KeySwitchID, true); err != nil {
+			return errors.Wrap(err, "unable to register switch ID flag on VPC Switch Port list")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,144 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/ethlink"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vmnic"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcp"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestPortTable(t *testing.T) {
+	prev := vpc.SetBackend(vpc.NewSimulator())
+	defer vpc.SetBackend(prev)
+
+	swID := vpc.GenID(vpc.ObjTypeSwitch)
+	sw, err := vpcsw.Create(vpcsw.Config{ID: swID, VNI: 123})
+	if err != nil {
+		t.Fatalf("unable to create switch: %v", err)
+	}
+	defer sw.Close()
+
+	addPort := func() vpc.ID {
+		portID := vpc.GenID(vpc.ObjTypeSwitchPort)
+		if err := sw.PortAdd(portID, nil); err != nil {
+			t.Fatalf("unable to add port: %v", err)
+		}
+
+		return portID
+	}
+
+	connect := func(portID, peerID vpc.ID) {
+		port, err := vpcp.Open(vpcp.Config{ID: portID, Writeable: true})
+		if err != nil {
+			t.Fatalf("unable to open port: %v", err)
+		}
+		defer port.Close()
+
+		if err := port.Connect(peerID); err != nil {
+			t.Fatalf("unable to connect port: %v", err)
+		}
+	}
+
+	nicID := vpc.GenID(vpc.ObjTypeNICVM)
+	nic, err := vmnic.Create(vmnic.Config{ID: nicID})
+	if err != nil {
+		t.Fatalf("unable to create VM NIC: %v", err)
+	}
+	defer nic.Close()
+
+	linkID := vpc.GenID(vpc.ObjTypeLinkEth)
+	link, err := ethlink.Create(ethlink.Config{ID: linkID, Name: "em0"})
+	if err != nil {
+		t.Fatalf("unable to create EthLink: %v", err)
+	}
+	defer link.Close()
+
+	nicPortID := addPort()
+	connect(nicPortID, nicID)
+	linkPortID := addPort()
+	connect(linkPortID, linkID)
+	freePortID := addPort()
+	lostPortID := addPort()
+
+	mgr, err := mgmt.New(nil)
+	if err != nil {
+		t.Fatalf("unable to open VPC Management handle: %v", err)
+	}
+	defer mgr.Close()
+
+	snap, err := mgr.Snapshot()
+	if err != nil {
+		t.Fatalf("unable to snapshot VPC objects: %v", err)
+	}
+
+	// A peer destroyed while the snapshot was taken is not in the snapshot.
+	lostPort, found := snap.Port(lostPortID)
+	if !found {
+		t.Fatalf("port %s not in the snapshot", lostPortID)
+	}
+	lostPeerID := vpc.GenID(vpc.ObjTypeNICVM)
+	lostPort.Peer = lostPeerID
+
+	snapSW, found := snap.Switch(swID)
+	if !found {
+		t.Fatalf("switch %s not in the snapshot", swID)
+	}
+
+	table := PortTable(snap, snapSW)
+	if table.Name != "ports" || !table.Total {
+		t.Fatalf("unexpected table %q (total: %t)", table.Name, table.Total)
+	}
+
+	unitName := func(id vpc.ID) string {
+		obj, found := snap.Lookup(id)
+		if !found {
+			t.Fatalf("object %s not in the snapshot", id)
+		}
+
+		switch obj := obj.(type) {
+		case *mgmt.Port:
+			return obj.UnitName
+		case *mgmt.VMNIC:
+			return obj.UnitName
+		case *mgmt.EthLink:
+			return obj.UnitName
+		default:
+			t.Fatalf("unexpected object %T", obj)
+			return ""
+		}
+	}
+
+	type peer struct {
+		Port, Peer, PeerType, PeerID interface{}
+	}
+
+	var got []peer
+	for _, rec := range table.Records {
+		r := rec.(portRecord)
+		got = append(got, peer{Port: r.name, Peer: r.peer, PeerType: r.peerType, PeerID: r.peerID})
+	}
+
+	want := []peer{
+		{Port: unitName(nicPortID), Peer: unitName(nicID), PeerType: vpc.ObjTypeNICVM, PeerID: nicID},
+		{Port: unitName(linkPortID), Peer: unitName(linkID), PeerType: vpc.ObjTypeLinkEth, PeerID: linkID},
+		{Port: unitName(freePortID)},
+		{Port: unitName(lostPortID), PeerID: lostPeerID},
+	}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Fatalf("ports diff: (-got +want)\n%s", diff)
+	}
+
+	if _, found := lookupPeer(snap, lostPort); found {
+		t.Errorf("peer of port %s found", lostPortID)
+	}
+
+	freePort, _ := snap.Port(freePortID)
+	if _, found := lookupPeer(snap, freePort); found {
+		t.Errorf("peer of port %s found", freePortID)
+	}
+}
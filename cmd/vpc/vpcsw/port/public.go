@@ -4,6 +4,7 @@ import (
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port/add"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port/connect"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port/disconnect"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port/list"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port/remove"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/pkg/errors"
@@ -26,7 +27,7 @@ var Cmd = &command.Command{
 			add.Cmd,
 			connect.Cmd,
 			disconnect.Cmd,
-			//list.Cmd,
+			list.Cmd,
 			remove.Cmd,
 		}
 
@@ -3,6 +3,7 @@ package remove
 import (
 	"fmt"
 
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/joyent/freebsd-vpc/internal/command/flag"
@@ -15,9 +16,11 @@ import (
 )
 
 const (
-	_CmdName     = "remove"
-	_KeyPortID   = config.KeySWPortRemovePortID
-	_KeySwitchID = config.KeySWPortRemoveSwitchID
+	_CmdName         = "remove"
+	_KeyPortID       = config.KeySWPortRemovePortID
+	_KeySwitchID     = config.KeySWPortRemoveSwitchID
+	_KeyRetry        = config.KeySWPortRemoveRetry
+	_KeyRetryTimeout = config.KeySWPortRemoveRetryTimeout
 )
 
 var Cmd = &command.Command{
@@ -47,6 +50,10 @@ var Cmd = &command.Command{
 			return errors.Wrap(err, "unable to register Switch ID flag for VPC Switch Port add")
 		}
 
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC Switch Port remove")
+		}
+
 		return nil
 	},
 }
@@ -54,6 +61,12 @@ var Cmd = &command.Command{
 func runE(cmd *cobra.Command, args []string) error {
 	cons := conswriter.GetTerminal()
 
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
 	cons.Write([]byte(fmt.Sprintf("Removing Port from VPC Switch...")))
 
 	// 1) get switch ID
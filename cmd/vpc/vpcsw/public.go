@@ -3,8 +3,11 @@ package vpcsw
 import (
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/create"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/destroy"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/get"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/list"
 	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/port"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/reset"
+	"github.com/joyent/freebsd-vpc/cmd/vpc/vpcsw/set"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -25,8 +28,11 @@ var Cmd = &command.Command{
 		subCommands := command.Commands{
 			create.Cmd,
 			destroy.Cmd,
+			get.Cmd,
 			list.Cmd,
 			port.Cmd,
+			reset.Cmd,
+			set.Cmd,
 		}
 
 		if err := self.Register(subCommands); err != nil {
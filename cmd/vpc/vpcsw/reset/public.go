@@ -0,0 +1,90 @@
+package reset
+
+import (
+	"fmt"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName         = "reset"
+	_KeySwitchID     = config.KeySWResetSwitchID
+	_KeyRetry        = config.KeySWResetRetry
+	_KeyRetryTimeout = config.KeySWResetRetryTimeout
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "reset a VPC switch",
+		Long:         "reset a VPC switch by removing all of its ports and bringing it down",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+
+		RunE: runE,
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddSwitchID(self, _KeySwitchID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Switch reset")
+		}
+
+		if err := flag.AddRetry(self, _KeyRetry, _KeyRetryTimeout); err != nil {
+			return errors.Wrap(err, "unable to register retry flags on VPC Switch reset")
+		}
+
+		return nil
+	},
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	cons := conswriter.GetTerminal()
+
+	retryPolicy, err := flag.GetRetryPolicy(viper.GetViper(), _KeyRetry, _KeyRetryTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unable to get retry policy")
+	}
+	vpc.SetRetryPolicy(retryPolicy)
+
+	cons.Write([]byte(fmt.Sprintf("Resetting VPC Switch...")))
+
+	id, err := flag.GetID(viper.GetViper(), _KeySwitchID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get VPC ID")
+	}
+
+	switchCfg := vpcsw.Config{
+		ID:        id,
+		Writeable: true,
+	}
+
+	log.Info().Object("cfg", switchCfg).Str("op", "reset").Msg("vpc_ctl")
+
+	vpcSwitch, err := vpcsw.Open(switchCfg)
+	if err != nil {
+		return errors.Wrap(err, "unable to open VPC Switch")
+	}
+	defer vpcSwitch.Close()
+
+	if err := vpcSwitch.Reset(); err != nil {
+		return errors.Wrap(err, "unable to reset VPC Switch")
+	}
+
+	cons.Write([]byte("done.\n"))
+
+	return nil
+}
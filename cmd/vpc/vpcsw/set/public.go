@@ -0,0 +1,107 @@
+package set
+
+import (
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/command/flag"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	_CmdName     = "set"
+	_KeyDown     = config.KeySWSetDown
+	_KeySwitchID = config.KeySWSetSwitchID
+	_KeyUp       = config.KeySWSetUp
+)
+
+var Cmd = &command.Command{
+	Name: _CmdName,
+
+	Cobra: &cobra.Command{
+		Use:          _CmdName,
+		Short:        "set VPC switch information",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if viper.GetBool(_KeyUp) && viper.GetBool(_KeyDown) {
+				return errors.New("--up and --down are mutually exclusive")
+			}
+
+			return nil
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := flag.GetID(viper.GetViper(), _KeySwitchID)
+			if err != nil {
+				return errors.Wrap(err, "unable to get VPC Switch ID")
+			}
+
+			switchCfg := vpcsw.Config{
+				ID:        id,
+				Writeable: true,
+			}
+			vpcSwitch, err := vpcsw.Open(switchCfg)
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Switch")
+			}
+			defer vpcSwitch.Close()
+
+			if viper.GetBool(_KeyUp) {
+				if err := vpcSwitch.SetState(true); err != nil {
+					return errors.Wrap(err, "unable to bring the VPC Switch up")
+				}
+			}
+
+			if viper.GetBool(_KeyDown) {
+				if err := vpcSwitch.SetState(false); err != nil {
+					return errors.Wrap(err, "unable to bring the VPC Switch down")
+				}
+			}
+
+			return nil
+		},
+	},
+
+	Setup: func(self *command.Command) error {
+		if err := flag.AddSwitchID(self, _KeySwitchID, true); err != nil {
+			return errors.Wrap(err, "unable to register ID flag on VPC Switch set")
+		}
+
+		{
+			const (
+				key          = _KeyUp
+				longName     = "up"
+				shortName    = ""
+				defaultValue = false
+				description  = "bring the VPC Switch up"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = _KeyDown
+				longName     = "down"
+				shortName    = ""
+				defaultValue = false
+				description  = "bring the VPC Switch down"
+			)
+
+			flags := self.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
@@ -5,10 +5,14 @@ VNI=123
 
 VPCSW0_ID=da64c3f3-095d-91e5-df01-5aabcfc52468
 ETHLINK0_ID=5c4acd32-1b8d-11e8-b408-0cc47a6c7d1e
-UPLINK_PORT_ID=ea58b648-203b-a707-cdf6-7a552c8d5295
+UPLINK_PORT_ID=ea58b648-203b-a707-cd02-7a552c8d5295
 
-VMNIC0_ID=07f95a11-6788-2ae7-c306-ba95cff1db38
-VPCP0_ID=fd436f9c-1f77-11e8-8002-0cc47a6c7d1e
+# VNIC UUIDs as stored by the control plane.  The kernel IDs of the VM NIC and
+# of the switch port it is connected to are derived from the VNIC UUID.
+VNIC0_UUID=7c9e6679-7425-40de-944b-e07fc1f90ae7
+VMNIC0_ID=$(vpc id --obj-type=vmnic --uuid=${VNIC0_UUID})
+VPCP0_ID=$(vpc id --obj-type=vpcp --uuid=${VNIC0_UUID})
 
-VMNIC1_ID=a774ba3a-1f77-11e8-8006-0cc47a6c7d1e
-VPCP1_ID=0ebf50e1-1f79-11e8-8002-0cc47a6c7d1e
+VNIC1_UUID=3b241101-e2bb-4255-8caf-4136c566a962
+VMNIC1_ID=$(vpc id --obj-type=vmnic --uuid=${VNIC1_UUID})
+VPCP1_ID=$(vpc id --obj-type=vpcp --uuid=${VNIC1_UUID})
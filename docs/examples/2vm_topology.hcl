@@ -0,0 +1,45 @@
+# The topology built by 2vm_setup.sh.  Apply it with:
+#
+#   vpc plan -f 2vm_topology.hcl
+#   vpc apply -f 2vm_topology.hcl
+#
+# and tear it down with:
+#
+#   vpc destroy -f 2vm_topology.hcl
+#
+# The VM NIC and switch port IDs are the ones printed by `vpc id` for the VNIC
+# UUIDs in 2vm_input.sh.
+
+switch "vpcsw0" {
+  id  = "da64c3f3-095d-91e5-df01-5aabcfc52468"
+  vni = 123
+
+  port "vmnic0" {
+    id    = "7c9e6679-7425-40de-9402-e07fc1f90ae7"
+    vmnic = "vmnic0"
+  }
+
+  port "vmnic1" {
+    id    = "3b241101-e2bb-4255-8c02-4036c566a962"
+    vmnic = "vmnic1"
+  }
+
+  port "uplink" {
+    id      = "ea58b648-203b-a707-cd02-7a552c8d5295"
+    uplink  = true
+    ethlink = "em0"
+  }
+}
+
+vmnic "vmnic0" {
+  id = "7c9e6679-7425-40de-9406-e07fc1f90ae7"
+}
+
+vmnic "vmnic1" {
+  id = "3b241101-e2bb-4255-8c06-4036c566a962"
+}
+
+ethlink "em0" {
+  id      = "5c4acd32-1b8d-11e8-b408-0cc47a6c7d1e"
+  l2-name = "em0"
+}
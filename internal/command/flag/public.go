@@ -2,8 +2,11 @@ package flag
 
 import (
 	"net"
+	"strconv"
+	"time"
 
 	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcnat"
 	"github.com/joyent/freebsd-vpc/internal/command"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -78,6 +81,160 @@ func AddMAC(cmd *command.Command, keyName string, required bool) error {
 	return nil
 }
 
+// AddMuxID adds the Mux ID flag to a given command.
+func AddMuxID(cmd *command.Command, keyName string, required bool) error {
+	key := keyName
+	const (
+		longName     = "mux-id"
+		shortName    = ""
+		defaultValue = ""
+		description  = "Specify the VPC Mux ID"
+	)
+
+	flags := cmd.Cobra.Flags()
+	flags.StringP(longName, shortName, defaultValue, description)
+	if required {
+		cmd.Cobra.MarkFlagRequired(longName)
+	}
+
+	viper.BindPFlag(key, flags.Lookup(longName))
+	viper.SetDefault(key, defaultValue)
+
+	return nil
+}
+
+// AddNATID adds the NAT ID flag to a given command.
+func AddNATID(cmd *command.Command, keyName string, required bool) error {
+	key := keyName
+	const (
+		longName     = "nat-id"
+		shortName    = ""
+		defaultValue = ""
+		description  = "Specify the VPC NAT ID"
+	)
+
+	flags := cmd.Cobra.Flags()
+	flags.StringP(longName, shortName, defaultValue, description)
+	if required {
+		cmd.Cobra.MarkFlagRequired(longName)
+	}
+
+	viper.BindPFlag(key, flags.Lookup(longName))
+	viper.SetDefault(key, defaultValue)
+
+	return nil
+}
+
+// NATRuleCfg names the Viper keys used by the flags describing a NAT rule.
+type NATRuleCfg struct {
+	Type          string
+	Protocol      string
+	Match         string
+	Port          string
+	Translate     string
+	TranslatePort string
+}
+
+// AddNATRule adds the flags describing a NAT rule to a given command.
+func AddNATRule(cmd *command.Command, cfg NATRuleCfg) error {
+	flags := cmd.Cobra.Flags()
+
+	{
+		key := cfg.Type
+		const (
+			longName     = "type"
+			shortName    = "t"
+			defaultValue = ""
+			description  = "Specify the NAT rule type (snat or dnat)"
+		)
+
+		flags.StringP(longName, shortName, defaultValue, description)
+		cmd.Cobra.MarkFlagRequired(longName)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	{
+		key := cfg.Protocol
+		const (
+			longName     = "protocol"
+			shortName    = "p"
+			defaultValue = "any"
+			description  = "Specify the protocol matched by the NAT rule (any, tcp, or udp)"
+		)
+
+		flags.StringP(longName, shortName, defaultValue, description)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	{
+		key := cfg.Match
+		const (
+			longName     = "match"
+			shortName    = "m"
+			defaultValue = ""
+			description  = "Specify the prefix matched by the NAT rule (e.g. 10.0.0.0/24)"
+		)
+
+		flags.StringP(longName, shortName, defaultValue, description)
+		cmd.Cobra.MarkFlagRequired(longName)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	{
+		key := cfg.Port
+		const (
+			longName     = "port"
+			shortName    = ""
+			defaultValue = 0
+			description  = "Specify the port matched by a DNAT rule"
+		)
+
+		flags.Uint16P(longName, shortName, defaultValue, description)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	{
+		key := cfg.Translate
+		const (
+			longName     = "translate"
+			shortName    = "x"
+			defaultValue = ""
+			description  = "Specify the address matching traffic is translated to"
+		)
+
+		flags.StringP(longName, shortName, defaultValue, description)
+		cmd.Cobra.MarkFlagRequired(longName)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	{
+		key := cfg.TranslatePort
+		const (
+			longName     = "translate-port"
+			shortName    = ""
+			defaultValue = 0
+			description  = "Specify the port matching traffic is translated to by a DNAT rule"
+		)
+
+		flags.Uint16P(longName, shortName, defaultValue, description)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	return nil
+}
+
 // AddPortID adds the Port ID flag to a given command.
 func AddPortID(cmd *command.Command, keyName string, required bool) error {
 	key := keyName
@@ -100,6 +257,86 @@ func AddPortID(cmd *command.Command, keyName string, required bool) error {
 	return nil
 }
 
+// AddRetry adds the flags controlling the vpc.RetryPolicy of a destructive
+// command.
+func AddRetry(cmd *command.Command, keyRetry, keyRetryTimeout string) error {
+	flags := cmd.Cobra.Flags()
+
+	{
+		key := keyRetry
+		const (
+			longName     = "retry"
+			shortName    = ""
+			defaultValue = 0
+			description  = "Number of times a busy VPC object is retried"
+		)
+
+		flags.IntP(longName, shortName, defaultValue, description)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	{
+		key := keyRetryTimeout
+		const (
+			longName     = "retry-timeout"
+			shortName    = ""
+			defaultValue = 30 * time.Second
+			description  = "Maximum time spent retrying a busy VPC object (0 for no limit)"
+		)
+
+		flags.DurationP(longName, shortName, defaultValue, description)
+
+		viper.BindPFlag(key, flags.Lookup(longName))
+		viper.SetDefault(key, defaultValue)
+	}
+
+	return nil
+}
+
+// AddRouterID adds the Router ID flag to a given command.
+func AddRouterID(cmd *command.Command, keyName string, required bool) error {
+	key := keyName
+	const (
+		longName     = "router-id"
+		shortName    = ""
+		defaultValue = ""
+		description  = "Specify the VPC Router ID"
+	)
+
+	flags := cmd.Cobra.Flags()
+	flags.StringP(longName, shortName, defaultValue, description)
+	if required {
+		cmd.Cobra.MarkFlagRequired(longName)
+	}
+
+	viper.BindPFlag(key, flags.Lookup(longName))
+	viper.SetDefault(key, defaultValue)
+
+	return nil
+}
+
+// AddStateFile adds the required state file flag to a given command.
+func AddStateFile(cmd *command.Command, keyName string) error {
+	key := keyName
+	const (
+		longName     = "file"
+		shortName    = "f"
+		defaultValue = ""
+		description  = "Specify the state file (.json, .yaml, or .yml)"
+	)
+
+	flags := cmd.Cobra.Flags()
+	flags.StringP(longName, shortName, defaultValue, description)
+	cmd.Cobra.MarkFlagRequired(longName)
+
+	viper.BindPFlag(key, flags.Lookup(longName))
+	viper.SetDefault(key, defaultValue)
+
+	return nil
+}
+
 // AddSwitchID adds the Switch ID flag to a given command.
 func AddSwitchID(cmd *command.Command, keyName string, required bool) error {
 	key := keyName
@@ -127,6 +364,26 @@ type VNICfg struct {
 	Required bool
 }
 
+// AddTopologyFile adds the required topology file flag to a given command.
+func AddTopologyFile(cmd *command.Command, keyName string) error {
+	key := keyName
+	const (
+		longName     = "file"
+		shortName    = "f"
+		defaultValue = ""
+		description  = "Specify the topology file (.hcl, .json, .yaml, or .yml)"
+	)
+
+	flags := cmd.Cobra.Flags()
+	flags.StringP(longName, shortName, defaultValue, description)
+	cmd.Cobra.MarkFlagRequired(longName)
+
+	viper.BindPFlag(key, flags.Lookup(longName))
+	viper.SetDefault(key, defaultValue)
+
+	return nil
+}
+
 // AddVMNICID adds the VM NIC ID flag to a given command.
 func AddVMNICID(cmd *command.Command, keyName string, required bool) error {
 	key := keyName
@@ -206,6 +463,57 @@ func GetMAC(v *viper.Viper, key string, id *vpc.ID) (mac net.HardwareAddr, err e
 	return mac, nil
 }
 
+// GetIPNet returns the IP address and prefix length found in the Viper key.
+// The host portion of the address is preserved (i.e. "10.0.0.1/24" is returned
+// as 10.0.0.1 with a /24 mask).
+func GetIPNet(v *viper.Viper, key string) (*net.IPNet, error) {
+	cidr := v.GetString(key)
+	if cidr == "" {
+		return nil, errors.Errorf("missing IP prefix for %q", key)
+	}
+
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse IP prefix %q", cidr)
+	}
+	ipNet.IP = ip
+
+	return ipNet, nil
+}
+
+// GetNATRule returns the NAT rule described by the Viper keys in cfg.
+func GetNATRule(v *viper.Viper, cfg NATRuleCfg) (vpcnat.Rule, error) {
+	ruleType, err := vpcnat.ParseRuleType(v.GetString(cfg.Type))
+	if err != nil {
+		return vpcnat.Rule{}, errors.Wrap(err, "unable to parse NAT rule type")
+	}
+
+	proto, err := vpcnat.ParseProtocol(v.GetString(cfg.Protocol))
+	if err != nil {
+		return vpcnat.Rule{}, errors.Wrap(err, "unable to parse NAT rule protocol")
+	}
+
+	match, err := GetIPNet(v, cfg.Match)
+	if err != nil {
+		return vpcnat.Rule{}, errors.Wrap(err, "unable to parse NAT rule match prefix")
+	}
+
+	xlateStr := v.GetString(cfg.Translate)
+	xlate := net.ParseIP(xlateStr)
+	if xlate == nil {
+		return vpcnat.Rule{}, errors.Errorf("unable to parse NAT rule translation address %q", xlateStr)
+	}
+
+	return vpcnat.Rule{
+		Type:          ruleType,
+		Protocol:      proto,
+		Match:         match,
+		Port:          uint16(v.GetInt(cfg.Port)),
+		Translate:     xlate,
+		TranslatePort: uint16(v.GetInt(cfg.TranslatePort)),
+	}, nil
+}
+
 // GetPortID returns the VPC ID found in the Viper key.
 func GetPortID(v *viper.Viper, key string) (id vpc.ID, err error) {
 	portIDStr := v.GetString(key)
@@ -220,6 +528,25 @@ func GetPortID(v *viper.Viper, key string) (id vpc.ID, err error) {
 	return id, nil
 }
 
+// GetRetryPolicy returns the vpc.RetryPolicy described by the Viper keys.
+func GetRetryPolicy(v *viper.Viper, keyRetry, keyRetryTimeout string) (vpc.RetryPolicy, error) {
+	retries := v.GetInt(keyRetry)
+	if retries < 0 {
+		return vpc.RetryPolicy{}, errors.Errorf("retry count %d can not be negative", retries)
+	}
+
+	timeout := v.GetDuration(keyRetryTimeout)
+	if timeout < 0 {
+		return vpc.RetryPolicy{}, errors.Errorf("retry timeout %s can not be negative", timeout)
+	}
+
+	return vpc.RetryPolicy{
+		Retries: retries,
+		Timeout: timeout,
+		Jitter:  vpc.DefaultRetryJitter,
+	}, nil
+}
+
 // GetSwitchID returns the VPC ID found in the Viper key.
 func GetSwitchID(v *viper.Viper, key string) (id vpc.ID, err error) {
 	switchIDStr := v.GetString(key)
@@ -234,6 +561,37 @@ func GetSwitchID(v *viper.Viper, key string) (id vpc.ID, err error) {
 	return id, nil
 }
 
+// GetUDPAddr returns the UDP address found in the Viper key.  The port is
+// optional (i.e. both "192.0.2.1" and "192.0.2.1:4789" are accepted) and is
+// left as zero when omitted.
+func GetUDPAddr(v *viper.Viper, key string) (*net.UDPAddr, error) {
+	addrStr := v.GetString(key)
+	if addrStr == "" {
+		return nil, errors.Errorf("missing UDP address for %q", key)
+	}
+
+	if ip := net.ParseIP(addrStr); ip != nil {
+		return &net.UDPAddr{IP: ip}, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(addrStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse UDP address %q", addrStr)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, errors.Errorf("unable to parse IP address %q", host)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse UDP port %q", portStr)
+	}
+
+	return &net.UDPAddr{IP: ip, Port: int(port)}, nil
+}
+
 // AddID adds the ID flag to a given command.
 func AddID(cmd *command.Command, keyName string, required bool) error {
 	key := keyName
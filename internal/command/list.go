@@ -0,0 +1,117 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var _ObjectColumns = []Column{
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "id", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "unit", Align: tablewriter.ALIGN_RIGHT, Wide: true},
+}
+
+// _ObjectRecord is a VPC object in the output of a list command.
+type _ObjectRecord struct {
+	name   string
+	id     vpc.ID
+	unitNo uint32
+}
+
+func (r _ObjectRecord) Values() []interface{} {
+	return []interface{}{r.name, r.id, r.unitNo}
+}
+
+// NewObjectList returns a "list" command that lists the VPC objects of
+// objType by name and ID.  The sort order is read from the sortByKey config
+// key.
+func NewObjectList(objType vpc.ObjType, short string, sortByKey string) *Command {
+	return &Command{
+		Name: "list",
+
+		Cobra: &cobra.Command{
+			Use:          "list",
+			Aliases:      []string{"ls"},
+			Short:        short,
+			SilenceUsage: true,
+			Args:         cobra.NoArgs,
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				return nil
+			},
+
+			RunE: func(cmd *cobra.Command, args []string) error {
+				renderer, err := NewRenderer(conswriter.GetTerminal())
+				if err != nil {
+					return errors.Wrap(err, "unable to configure output")
+				}
+
+				mgr, err := mgmt.New(nil)
+				if err != nil {
+					return errors.Wrapf(err, "unable to open VPC Management handle")
+				}
+				defer mgr.Close()
+
+				objHeaders, err := mgr.GetAllIDs(objType)
+				if err != nil {
+					return errors.Wrapf(err, "unable to count %s VPC objects", objType)
+				}
+
+				sortBy := viper.GetString(sortByKey)
+				switch k := strings.ToLower(sortBy); k {
+				case "id":
+					sort.SliceStable(objHeaders, func(i, j int) bool { return bytes.Compare(objHeaders[i].ID().Bytes(), objHeaders[j].ID().Bytes()) < 0 })
+				case "name":
+					sort.SliceStable(objHeaders, func(i, j int) bool { return objHeaders[i].UnitName() < objHeaders[j].UnitName() })
+				default:
+					return errors.Errorf("unsupported sort option: %q", sortBy)
+				}
+
+				records := make([]Record, 0, len(objHeaders))
+				for _, hdr := range objHeaders {
+					records = append(records, _ObjectRecord{
+						name:   hdr.UnitName(),
+						id:     hdr.ID(),
+						unitNo: hdr.UnitNo(),
+					})
+				}
+
+				return renderer.Render(Table{
+					Columns: _ObjectColumns,
+					Records: records,
+					Total:   true,
+				})
+			},
+		},
+
+		Setup: func(self *Command) error {
+			{
+				const (
+					longName     = "sort-by"
+					shortName    = "s"
+					defaultValue = "id"
+				)
+				sortOptions := []string{"id", "name"}
+				sortOptionsStr := strings.Join(sortOptions, ", ")
+				description := fmt.Sprintf("Change the sort order within a given type: %s", sortOptionsStr)
+
+				flags := self.Cobra.Flags()
+				flags.StringP(longName, shortName, defaultValue, description)
+
+				viper.BindPFlag(sortByKey, flags.Lookup(longName))
+				viper.SetDefault(sortByKey, defaultValue)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,452 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OutputFormat selects how a Renderer formats records.
+type OutputFormat string
+
+const (
+	OutputTable OutputFormat = "table"
+	OutputWide  OutputFormat = "wide"
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+)
+
+// OutputFormats is the list of supported output formats.
+var OutputFormats = []OutputFormat{OutputTable, OutputJSON, OutputYAML, OutputWide}
+
+// ParseOutputFormat parses the value of the --output flag.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	for _, f := range OutputFormats {
+		if strings.ToLower(s) == string(f) {
+			return f, nil
+		}
+	}
+
+	return "", errors.Errorf("unsupported output format %q", s)
+}
+
+// Column describes one field of a record.
+type Column struct {
+	// Name is used as the table header, as the key in JSON and YAML output,
+	// and to select the column with --columns.
+	Name string
+
+	// Align is the tablewriter alignment of the column.
+	Align int
+
+	// Wide columns are only displayed with --output=wide, unless they are
+	// selected with --columns.
+	Wide bool
+
+	// Width is the minimum width of the column in streamed table output, where
+	// each row is printed before the following rows are known.
+	Width int
+}
+
+// Record is a row of command output.  Values returns one value per Column of
+// the Table the record is rendered in.  Values that implement fmt.Stringer are
+// rendered as strings, everything else is rendered natively in JSON and YAML.
+// A nil value is rendered as "-" in a table and as null in JSON and YAML.
+type Record interface {
+	Values() []interface{}
+}
+
+// Table is a set of records of the same type.
+type Table struct {
+	// Name is the key of the table when several tables are rendered as a single
+	// JSON or YAML document.
+	Name string
+
+	Columns []Column
+	Records []Record
+
+	// Total adds a footer with the number of records to table output.
+	Total bool
+}
+
+// Renderer formats the output of commands according to the global --output,
+// --no-headers, and --columns flags.
+type Renderer struct {
+	w         io.Writer
+	format    OutputFormat
+	noHeaders bool
+	columns   []string
+}
+
+// NewRenderer returns a Renderer writing to w that is configured from the
+// global output flags.
+func NewRenderer(w io.Writer) (*Renderer, error) {
+	format, err := ParseOutputFormat(viper.GetString(config.KeyOutputFormat))
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, name := range viper.GetStringSlice(config.KeyOutputColumns) {
+		for _, col := range strings.Split(name, ",") {
+			if col = strings.TrimSpace(strings.ToLower(col)); col != "" {
+				columns = append(columns, col)
+			}
+		}
+	}
+
+	return &Renderer{
+		w:         w,
+		format:    format,
+		noHeaders: viper.GetBool(config.KeyOutputNoHeaders),
+		columns:   columns,
+	}, nil
+}
+
+// Format returns the output format of the Renderer.
+func (r *Renderer) Format() OutputFormat {
+	return r.format
+}
+
+// Structured returns true if the output is meant to be parsed, i.e. JSON or
+// YAML.  Commands only print informational messages for unstructured output.
+func (r *Renderer) Structured() bool {
+	return r.format == OutputJSON || r.format == OutputYAML
+}
+
+// Render writes tables.  Table output renders each table in turn.  JSON and
+// YAML output render a single table as a list of objects and several tables as
+// an object keyed by table name.
+func (r *Renderer) Render(tables ...Table) error {
+	selected := make([][]int, len(tables))
+	for i, t := range tables {
+		selected[i] = r.selectColumns(t.Columns)
+	}
+
+	if err := r.checkColumns(tables); err != nil {
+		return err
+	}
+
+	switch r.format {
+	case OutputJSON, OutputYAML:
+		if len(tables) == 1 {
+			return r.encode(r.records(tables[0], selected[0]))
+		}
+
+		doc := make(yaml.MapSlice, 0, len(tables))
+		for i, t := range tables {
+			doc = append(doc, yaml.MapItem{Key: t.Name, Value: r.records(t, selected[i])})
+		}
+		return r.encode(doc)
+	default:
+		for i, t := range tables {
+			r.renderTable(t, selected[i], nil, !r.noHeaders)
+		}
+		return nil
+	}
+}
+
+// Stream returns a Stream that renders records of the given columns as they
+// are produced.
+func (r *Renderer) Stream(cols []Column) (*Stream, error) {
+	t := Table{Columns: cols}
+	if err := r.checkColumns([]Table{t}); err != nil {
+		return nil, err
+	}
+
+	selected := r.selectColumns(cols)
+	widths := make([]int, len(selected))
+	for i, idx := range selected {
+		widths[i] = cols[idx].Width
+		if n := len(cols[idx].Name); !r.noHeaders && n > widths[i] {
+			widths[i] = n
+		}
+	}
+
+	return &Stream{
+		r:        r,
+		table:    t,
+		selected: selected,
+		widths:   widths,
+	}, nil
+}
+
+// Stream renders records one at a time.  Table output only prints the header
+// before the first record and pads every cell to the Width of its column so
+// that rows line up.  A value wider than its column widens the column for the
+// rows that follow.  JSON output prints one object per line, and YAML output
+// prints one document per record.
+type Stream struct {
+	r        *Renderer
+	table    Table
+	selected []int
+	widths   []int
+	written  bool
+}
+
+// Write renders a single record.
+func (s *Stream) Write(rec Record) error {
+	defer func() { s.written = true }()
+
+	switch s.r.format {
+	case OutputJSON:
+		buf, err := marshalJSON(s.r.record(rec, s.table.Columns, s.selected))
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(s.r.w, "%s\n", buf)
+		return err
+	case OutputYAML:
+		if _, err := io.WriteString(s.r.w, "---\n"); err != nil {
+			return err
+		}
+		return s.r.encode(s.r.record(rec, s.table.Columns, s.selected))
+	default:
+		values := rec.Values()
+		for i, idx := range s.selected {
+			if n := len(formatValue(values[idx])); n > s.widths[i] {
+				s.widths[i] = n
+			}
+		}
+
+		t := s.table
+		t.Records = []Record{rec}
+		s.r.renderTable(t, s.selected, s.widths, !s.r.noHeaders && !s.written)
+		return nil
+	}
+}
+
+// selectColumns returns the indexes of the columns to render.
+func (r *Renderer) selectColumns(cols []Column) []int {
+	selected := make([]int, 0, len(cols))
+
+	if len(r.columns) == 0 {
+		for i, col := range cols {
+			if !col.Wide || r.format == OutputWide {
+				selected = append(selected, i)
+			}
+		}
+
+		return selected
+	}
+
+	for _, name := range r.columns {
+		for i, col := range cols {
+			if name == col.Name {
+				selected = append(selected, i)
+			}
+		}
+	}
+
+	return selected
+}
+
+// checkColumns verifies that every column requested with --columns exists in
+// at least one of the tables.
+func (r *Renderer) checkColumns(tables []Table) error {
+	known := make(map[string]bool)
+	names := make([]string, 0)
+	for _, t := range tables {
+		for _, col := range t.Columns {
+			if !known[col.Name] {
+				known[col.Name] = true
+				names = append(names, col.Name)
+			}
+		}
+	}
+
+	for _, name := range r.columns {
+		if !known[name] {
+			return errors.Errorf("unsupported column %q (valid columns: %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	return nil
+}
+
+// renderTable writes t as a table.  minWidths, if not nil, holds the minimum
+// width of each selected column.
+func (r *Renderer) renderTable(t Table, selected []int, minWidths []int, headers bool) {
+	table := tablewriter.NewWriter(r.w)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetAutoWrapText(false)
+
+	aligns := make([]int, len(selected))
+	for i, idx := range selected {
+		aligns[i] = t.Columns[idx].Align
+	}
+	table.SetColumnAlignment(aligns)
+	for i, width := range minWidths {
+		table.SetColMinWidth(i, width)
+	}
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+
+	if headers {
+		header := make([]string, len(selected))
+		for i, idx := range selected {
+			header[i] = strings.Replace(t.Columns[idx].Name, "-", " ", -1)
+		}
+		table.SetHeader(header)
+	}
+
+	for _, rec := range t.Records {
+		values := rec.Values()
+
+		row := make([]string, len(selected))
+		for i, idx := range selected {
+			row[i] = formatValue(values[idx])
+		}
+		table.Append(row)
+	}
+
+	if t.Total && headers && len(selected) > 0 {
+		footer := make([]string, len(selected))
+		footer[0] = "total"
+		if len(selected) > 1 {
+			footer[1] = strconv.Itoa(len(t.Records))
+		} else {
+			footer[0] = "total " + strconv.Itoa(len(t.Records))
+		}
+		table.SetFooter(footer)
+	}
+
+	table.Render()
+}
+
+func (r *Renderer) records(t Table, selected []int) []yaml.MapSlice {
+	recs := make([]yaml.MapSlice, 0, len(t.Records))
+	for _, rec := range t.Records {
+		recs = append(recs, r.record(rec, t.Columns, selected))
+	}
+
+	return recs
+}
+
+func (r *Renderer) record(rec Record, cols []Column, selected []int) yaml.MapSlice {
+	values := rec.Values()
+
+	obj := make(yaml.MapSlice, 0, len(selected))
+	for _, idx := range selected {
+		obj = append(obj, yaml.MapItem{Key: cols[idx].Name, Value: encodeValue(values[idx])})
+	}
+
+	return obj
+}
+
+func (r *Renderer) encode(v interface{}) error {
+	var buf []byte
+	var err error
+
+	switch r.format {
+	case OutputJSON:
+		if buf, err = marshalJSON(v); err != nil {
+			return err
+		}
+
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf, "", "  "); err != nil {
+			return errors.Wrap(err, "unable to indent JSON output")
+		}
+		out.WriteByte('\n')
+		buf = out.Bytes()
+	case OutputYAML:
+		if buf, err = yaml.Marshal(v); err != nil {
+			return errors.Wrap(err, "unable to encode YAML output")
+		}
+	}
+
+	_, err = r.w.Write(buf)
+	return err
+}
+
+// marshalJSON encodes v as JSON preserving the key order of yaml.MapSlice
+// values.
+func marshalJSON(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to encode JSON key")
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+
+			val, err := marshalJSON(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(val)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	case []yaml.MapSlice:
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			val, err := marshalJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(val)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	default:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to encode JSON output")
+		}
+		return buf, nil
+	}
+}
+
+// encodeValue converts a record value into the value used in JSON and YAML
+// output.
+func encodeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// formatValue converts a record value into a table cell.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
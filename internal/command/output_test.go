@@ -0,0 +1,264 @@
+package command_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/joyent/freebsd-vpc/internal/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/viper"
+)
+
+type testRecord struct {
+	name  string
+	count int
+	note  interface{}
+}
+
+func (r testRecord) Values() []interface{} {
+	return []interface{}{r.name, r.count, r.note}
+}
+
+var testColumns = []command.Column{
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "count", Align: tablewriter.ALIGN_RIGHT},
+	{Name: "note", Align: tablewriter.ALIGN_LEFT, Wide: true},
+}
+
+func testTable() command.Table {
+	return command.Table{
+		Name:    "things",
+		Columns: testColumns,
+		Records: []command.Record{
+			testRecord{name: "vpcsw0", count: 2, note: "uplink"},
+			testRecord{name: "vmnic12", count: 10},
+		},
+		Total: true,
+	}
+}
+
+// render renders tables with the given output flags and returns the output.
+func render(t *testing.T, format string, noHeaders bool, columns []string, tables ...command.Table) string {
+	t.Helper()
+
+	viper.Set(config.KeyOutputFormat, format)
+	viper.Set(config.KeyOutputNoHeaders, noHeaders)
+	viper.Set(config.KeyOutputColumns, columns)
+	defer viper.Reset()
+
+	var buf bytes.Buffer
+	r, err := command.NewRenderer(&buf)
+	if err != nil {
+		t.Fatalf("unable to create renderer: %v", err)
+	}
+
+	if err := r.Render(tables...); err != nil {
+		t.Fatalf("unable to render: %v", err)
+	}
+
+	return buf.String()
+}
+
+// fields splits table output into the whitespace separated fields of each
+// line.
+func fields(out string) [][]string {
+	var lines [][]string
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		lines = append(lines, strings.Fields(line))
+	}
+
+	return lines
+}
+
+func compareFields(t *testing.T, got string, want [][]string) {
+	t.Helper()
+
+	lines := fields(got)
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(want), len(lines), got)
+	}
+
+	for i := range want {
+		if strings.Join(lines[i], " ") != strings.Join(want[i], " ") {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestRenderer_Table(t *testing.T) {
+	out := render(t, "table", false, nil, testTable())
+	compareFields(t, out, [][]string{
+		{"NAME", "COUNT"},
+		{"vpcsw0", "2"},
+		{"vmnic12", "10"},
+		{},
+		{"TOTAL", "2"},
+	})
+}
+
+func TestRenderer_Wide(t *testing.T) {
+	out := render(t, "wide", false, nil, testTable())
+	compareFields(t, out, [][]string{
+		{"NAME", "COUNT", "NOTE"},
+		{"vpcsw0", "2", "uplink"},
+		{"vmnic12", "10", "-"},
+		{},
+		{"TOTAL", "2"},
+	})
+}
+
+func TestRenderer_NoHeaders(t *testing.T) {
+	out := render(t, "table", true, nil, testTable())
+	compareFields(t, out, [][]string{
+		{"vpcsw0", "2"},
+		{"vmnic12", "10"},
+	})
+}
+
+func TestRenderer_Columns(t *testing.T) {
+	// --columns selects wide columns and orders the output.
+	out := render(t, "table", false, []string{"note,name"}, testTable())
+	compareFields(t, out, [][]string{
+		{"NOTE", "NAME"},
+		{"uplink", "vpcsw0"},
+		{"-", "vmnic12"},
+		{},
+		{"TOTAL", "2"},
+	})
+
+	out = render(t, "json", false, []string{"count"}, testTable())
+	if want := "[\n  {\n    \"count\": 2\n  },\n  {\n    \"count\": 10\n  }\n]\n"; out != want {
+		t.Errorf("unexpected JSON output:\n%s", out)
+	}
+
+	viper.Set(config.KeyOutputFormat, "table")
+	viper.Set(config.KeyOutputColumns, []string{"bogus"})
+	defer viper.Reset()
+
+	r, err := command.NewRenderer(&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unable to create renderer: %v", err)
+	}
+
+	if err := r.Render(testTable()); err == nil || !strings.Contains(err.Error(), `unsupported column "bogus"`) {
+		t.Fatalf("expected an unsupported column error, got %v", err)
+	}
+}
+
+func TestRenderer_JSON(t *testing.T) {
+	// Wide columns are only included when they are selected.
+	out := render(t, "json", false, []string{"name", "count", "note"}, testTable())
+	want := `[
+  {
+    "name": "vpcsw0",
+    "count": 2,
+    "note": "uplink"
+  },
+  {
+    "name": "vmnic12",
+    "count": 10,
+    "note": null
+  }
+]
+`
+	if out != want {
+		t.Errorf("unexpected JSON output:\n%s\nexpected:\n%s", out, want)
+	}
+
+	// Several tables are keyed by table name.
+	other := command.Table{Name: "others", Columns: testColumns}
+	out = render(t, "json", false, []string{"name"}, testTable(), other)
+	want = `{
+  "things": [
+    {
+      "name": "vpcsw0"
+    },
+    {
+      "name": "vmnic12"
+    }
+  ],
+  "others": []
+}
+`
+	if out != want {
+		t.Errorf("unexpected JSON output:\n%s\nexpected:\n%s", out, want)
+	}
+}
+
+func TestRenderer_YAML(t *testing.T) {
+	out := render(t, "yaml", false, nil, testTable())
+	want := `- name: vpcsw0
+  count: 2
+- name: vmnic12
+  count: 10
+`
+	if out != want {
+		t.Errorf("unexpected YAML output:\n%s\nexpected:\n%s", out, want)
+	}
+}
+
+func TestRenderer_Stream(t *testing.T) {
+	cols := []command.Column{
+		{Name: "name", Align: tablewriter.ALIGN_LEFT, Width: 8},
+		{Name: "count", Align: tablewriter.ALIGN_RIGHT, Width: 5},
+	}
+	recs := []command.Record{
+		testRecord{name: "a", count: 1},
+		testRecord{name: "vmnic12", count: 12345},
+		testRecord{name: "bb", count: 22},
+	}
+
+	stream := func(format string) string {
+		viper.Set(config.KeyOutputFormat, format)
+		defer viper.Reset()
+
+		var buf bytes.Buffer
+		r, err := command.NewRenderer(&buf)
+		if err != nil {
+			t.Fatalf("unable to create renderer: %v", err)
+		}
+
+		s, err := r.Stream(cols)
+		if err != nil {
+			t.Fatalf("unable to create stream: %v", err)
+		}
+
+		for _, rec := range recs {
+			if err := s.Write(rec); err != nil {
+				t.Fatalf("unable to write record: %v", err)
+			}
+		}
+
+		return buf.String()
+	}
+
+	// Every row is rendered on its own, but the rows line up.
+	out := stream("table")
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows:\n%s", out)
+	}
+
+	for i, line := range lines {
+		if len(line) != len(lines[0]) {
+			t.Errorf("line %d is not aligned with the header:\n%s", i, out)
+		}
+	}
+
+	if got := fields(out)[0]; strings.Join(got, " ") != "NAME COUNT" {
+		t.Errorf("unexpected header %q", got)
+	}
+
+	if out := stream("json"); out != `{"name":"a","count":1}
+{"name":"vmnic12","count":12345}
+{"name":"bb","count":22}
+` {
+		t.Errorf("unexpected JSON stream:\n%s", out)
+	}
+
+	if out := stream("yaml"); !strings.HasPrefix(out, "---\nname: a\ncount: 1\n---\n") || strings.Count(out, "---\n") != 3 {
+		t.Errorf("unexpected YAML stream:\n%s", out)
+	}
+}
@@ -7,60 +7,154 @@ const (
 	DefaultMarkdownDir       = "./docs/md"
 	DefaultMarkdownURLPrefix = "/command"
 
+	KeyApplyFile = "apply.file"
+
+	KeyDestroyFile = "destroy.file"
+
 	KeyDocManDir            = "doc.mandir"
 	KeyDocMarkdownDir       = "doc.markdown-dir"
 	KeyDocMarkdownURLPrefix = "doc.markdown-url-prefix"
 
-	KeyEthLinkDestroyID  = "ethlink.destroy.ethlink-id"
-	KeyEthLinkListSortBy = "ethlink.list.sort-by"
+	KeyEthLinkDestroyID           = "ethlink.destroy.ethlink-id"
+	KeyEthLinkDestroyRetry        = "ethlink.destroy.retry"
+	KeyEthLinkDestroyRetryTimeout = "ethlink.destroy.retry-timeout"
+	KeyEthLinkListSortBy          = "ethlink.list.sort-by"
+
+	KeyIDObjType = "id.obj-type"
+	KeyIDUUID    = "id.uuid"
 
 	KeyListObjCounts = "list.obj-counts"
 	KeyListObjSortBy = "list.sort-by"
 	KeyListObjType   = "list.type"
+	KeyListWatch     = "list.watch"
+	KeyListWatchIntv = "list.watch-interval"
 
 	KeyLogFormat    = "log.format"
 	KeyLogLevel     = "log.level"
 	KeyLogStats     = "log.stats"
 	KeyLogTermColor = "log.use-color"
 
+	KeyMuxAttachMuxID         = "mux.attach.mux-id"
+	KeyMuxAttachPortID        = "mux.attach.port-id"
+	KeyMuxCreateEthLinkID     = "mux.create.ethlink-id"
+	KeyMuxCreateListenAddr    = "mux.create.listen-addr"
+	KeyMuxCreateMuxID         = "mux.create.mux-id"
+	KeyMuxDestroyMuxID        = "mux.destroy.mux-id"
+	KeyMuxDestroyRetry        = "mux.destroy.retry"
+	KeyMuxDestroyRetryTimeout = "mux.destroy.retry-timeout"
+	KeyMuxListSortBy          = "mux.list.sort-by"
+
+	KeyNATConnectEthLinkID    = "nat.connect.ethlink-id"
+	KeyNATConnectNATID        = "nat.connect.nat-id"
+	KeyNATConnectPortID       = "nat.connect.port-id"
+	KeyNATCreateNATID         = "nat.create.nat-id"
+	KeyNATDestroyNATID        = "nat.destroy.nat-id"
+	KeyNATDestroyRetry        = "nat.destroy.retry"
+	KeyNATDestroyRetryTimeout = "nat.destroy.retry-timeout"
+	KeyNATGetNATID            = "nat.get.nat-id"
+	KeyNATListSortBy          = "nat.list.sort-by"
+
+	KeyNATRuleAddMatch            = "nat.rule.add.match"
+	KeyNATRuleAddNATID            = "nat.rule.add.nat-id"
+	KeyNATRuleAddPort             = "nat.rule.add.port"
+	KeyNATRuleAddProtocol         = "nat.rule.add.protocol"
+	KeyNATRuleAddTranslate        = "nat.rule.add.translate"
+	KeyNATRuleAddTranslatePort    = "nat.rule.add.translate-port"
+	KeyNATRuleAddType             = "nat.rule.add.type"
+	KeyNATRuleRemoveMatch         = "nat.rule.remove.match"
+	KeyNATRuleRemoveNATID         = "nat.rule.remove.nat-id"
+	KeyNATRuleRemovePort          = "nat.rule.remove.port"
+	KeyNATRuleRemoveProtocol      = "nat.rule.remove.protocol"
+	KeyNATRuleRemoveTranslate     = "nat.rule.remove.translate"
+	KeyNATRuleRemoveTranslatePort = "nat.rule.remove.translate-port"
+	KeyNATRuleRemoveType          = "nat.rule.remove.type"
+
+	KeyOutputColumns   = "output.columns"
+	KeyOutputFormat    = "output.format"
+	KeyOutputNoHeaders = "output.no-headers"
+
+	KeyPlanFile = "plan.file"
+
 	KeyPGDatabase = "db.name"
 	KeyPGUser     = "db.username"
 	KeyPGPassword = "db.password"
 	KeyPGHost     = "db.host"
 	KeyPGPort     = "db.port"
 
-	KeySWPortAddEthLinkID          = "switch.port.add.ethlink-id"
-	KeySWPortAddID                 = "switch.port.add.id"
-	KeySWPortAddL2Name             = "switch.port.add.l2-name"
-	KeySWPortAddMAC                = "switch.port.add.mac"
-	KeySWPortAddSwitchID           = "switch.port.add.switch-id"
-	KeySWPortAddUplink             = "switch.port.add.uplink"
-	KeySWPortConnectInterfaceID    = "switch.port.connect.interface-id"
-	KeySWPortConnectPortID         = "switch.port.connect.port-id"
-	KeySWPortDisconnectInterfaceID = "switch.port.disconnect.interface-id"
-	KeySWPortDisconnectPortID      = "switch.port.disconnect.port-id"
+	KeyPruneDryRun       = "prune.dry-run"
+	KeyPruneLedger       = "prune.ledger"
+	KeyPruneMinAge       = "prune.min-age"
+	KeyPruneObjType      = "prune.obj-type"
+	KeyPruneRetry        = "prune.retry"
+	KeyPruneRetryTimeout = "prune.retry-timeout"
+	KeyPruneYes          = "prune.yes"
+
+	KeyRouterCreateRouterID      = "router.create.router-id"
+	KeyRouterDestroyRouterID     = "router.destroy.router-id"
+	KeyRouterDestroyRetry        = "router.destroy.retry"
+	KeyRouterDestroyRetryTimeout = "router.destroy.retry-timeout"
+	KeyRouterIntfAddAddr         = "router.interface.add.addr"
+	KeyRouterIntfAddPortID       = "router.interface.add.port-id"
+	KeyRouterIntfAddRouterID     = "router.interface.add.router-id"
+	KeyRouterListSortBy          = "router.list.sort-by"
+	KeyRouterRouteAddDestination = "router.route.add.destination"
+	KeyRouterRouteAddPortID      = "router.route.add.port-id"
+	KeyRouterRouteAddRouterID    = "router.route.add.router-id"
 
-	KeySWPortRemovePortID   = "switch.port.remove.port-id"
-	KeySWPortRemoveSwitchID = "switch.port.remove.switch-id"
+	KeySWPortAddEthLinkID           = "switch.port.add.ethlink-id"
+	KeySWPortAddID                  = "switch.port.add.id"
+	KeySWPortAddL2Name              = "switch.port.add.l2-name"
+	KeySWPortAddMAC                 = "switch.port.add.mac"
+	KeySWPortAddSwitchID            = "switch.port.add.switch-id"
+	KeySWPortAddUplink              = "switch.port.add.uplink"
+	KeySWPortConnectInterfaceID     = "switch.port.connect.interface-id"
+	KeySWPortConnectPortID          = "switch.port.connect.port-id"
+	KeySWPortDisconnectInterfaceID  = "switch.port.disconnect.interface-id"
+	KeySWPortDisconnectPortID       = "switch.port.disconnect.port-id"
+	KeySWPortDisconnectRetry        = "switch.port.disconnect.retry"
+	KeySWPortDisconnectRetryTimeout = "switch.port.disconnect.retry-timeout"
+	KeySWPortListSwitchID           = "switch.port.list.switch-id"
+
+	KeySWPortRemovePortID       = "switch.port.remove.port-id"
+	KeySWPortRemoveSwitchID     = "switch.port.remove.switch-id"
+	KeySWPortRemoveRetry        = "switch.port.remove.retry"
+	KeySWPortRemoveRetryTimeout = "switch.port.remove.retry-timeout"
 
 	KeyShellAutoCompBashDir = "shell.autocomplete.bash-dir"
 
-	KeySWCreateSwitchID  = "switch.create.switch-id"
-	KeySWCreateSwitchMAC = "switch.create.switch-mac"
-	KeySWCreateVNI       = "switch.create.vni"
-	KeySWDestroySwitchID = "switch.destroy.switch-id"
+	KeySWCreateSwitchID      = "switch.create.switch-id"
+	KeySWCreateSwitchMAC     = "switch.create.switch-mac"
+	KeySWCreateVNI           = "switch.create.vni"
+	KeySWDestroySwitchID     = "switch.destroy.switch-id"
+	KeySWDestroyRetry        = "switch.destroy.retry"
+	KeySWDestroyRetryTimeout = "switch.destroy.retry-timeout"
+	KeySWGetSwitchID         = "switch.get.switch-id"
+	KeySWResetSwitchID       = "switch.reset.switch-id"
+	KeySWResetRetry          = "switch.reset.retry"
+	KeySWResetRetryTimeout   = "switch.reset.retry-timeout"
+	KeySWSetDown             = "switch.set.down"
+	KeySWSetSwitchID         = "switch.set.switch-id"
+	KeySWSetUp               = "switch.set.up"
+
+	KeyStateExportFile          = "state.export.file"
+	KeyStateRestoreFile         = "state.restore.file"
+	KeyStateRestoreSkipExisting = "state.restore.skip-existing"
 
 	KeyUseGoogleAgent = "general.enable-agent"
 	KeyUsePager       = "general.use-pager"
 	KeyUseUTC         = "general.utc"
 
-	KeyVMNICCreateID    = "vmnic.create.id"
-	KeyVMNICCreateMAC   = "vmnic.create.mac"
-	KeyVMNICDestroyID   = "vmnic.destroy.id"
-	KeyVMNICGetNQueues  = "vmnic.get.num-queues"
-	KeyVMNICGetVMNICID  = "vmnic.get.vmnic-id"
-	KeyVMNICSetFreeze   = "vmnic.set.freeze"
-	KeyVMNICSetNQueues  = "vmnic.set.num-queues"
-	KeyVMNICSetUnfreeze = "vmnic.set.unfreeze"
-	KeyVMNICSetVMNICID  = "vmnic.set.vmnic-id"
+	KeyVersionKernel = "version.kernel"
+
+	KeyVMNICCreateID            = "vmnic.create.id"
+	KeyVMNICCreateMAC           = "vmnic.create.mac"
+	KeyVMNICDestroyID           = "vmnic.destroy.id"
+	KeyVMNICDestroyRetry        = "vmnic.destroy.retry"
+	KeyVMNICDestroyRetryTimeout = "vmnic.destroy.retry-timeout"
+	KeyVMNICGetNQueues          = "vmnic.get.num-queues"
+	KeyVMNICGetVMNICID          = "vmnic.get.vmnic-id"
+	KeyVMNICSetFreeze           = "vmnic.set.freeze"
+	KeyVMNICSetNQueues          = "vmnic.set.num-queues"
+	KeyVMNICSetUnfreeze         = "vmnic.set.unfreeze"
+	KeyVMNICSetVMNICID          = "vmnic.set.vmnic-id"
 )
@@ -0,0 +1,216 @@
+package prune
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/ethlink"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vmnic"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcp"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
+	"github.com/pkg/errors"
+)
+
+// Candidate is an orphan along with the time it was first seen orphaned.
+type Candidate struct {
+	mgmt.Orphan
+	FirstSeen time.Time
+}
+
+// Age returns how long the candidate has been orphaned as of now.
+func (c Candidate) Age(now time.Time) time.Duration {
+	return now.Sub(c.FirstSeen)
+}
+
+// Ledger records when each orphan was first seen.  The kernel does not record
+// when a VPC object was created, so the age of an orphan is the time elapsed
+// since the first prune that found it.
+type Ledger struct {
+	path string
+
+	FirstSeen map[vpc.ID]time.Time `json:"first-seen"`
+}
+
+// LoadLedger reads the ledger at path.  A missing ledger is empty.  An empty
+// path returns an in-memory ledger that is never saved.
+func LoadLedger(path string) (*Ledger, error) {
+	l := &Ledger{
+		path:      path,
+		FirstSeen: make(map[vpc.ID]time.Time),
+	}
+
+	if path == "" {
+		return l, nil
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return l, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "unable to read orphan ledger %q", path)
+	}
+
+	if err := json.Unmarshal(buf, l); err != nil {
+		return nil, errors.Wrapf(err, "unable to decode orphan ledger %q", path)
+	}
+
+	if l.FirstSeen == nil {
+		l.FirstSeen = make(map[vpc.ID]time.Time)
+	}
+
+	return l, nil
+}
+
+// Observe records the orphans found at time now and returns them as
+// Candidates, preserving their order.  Objects that are no longer orphans are
+// forgotten.
+func (l *Ledger) Observe(orphans []mgmt.Orphan, now time.Time) []Candidate {
+	firstSeen := make(map[vpc.ID]time.Time, len(orphans))
+	candidates := make([]Candidate, 0, len(orphans))
+	for _, orphan := range orphans {
+		seen, found := l.FirstSeen[orphan.ID]
+		if !found || seen.After(now) {
+			seen = now
+		}
+		firstSeen[orphan.ID] = seen
+
+		candidates = append(candidates, Candidate{Orphan: orphan, FirstSeen: seen})
+	}
+	l.FirstSeen = firstSeen
+
+	return candidates
+}
+
+// Check returns an error if the orphans of the given object types can't be
+// found in snap.  Switches and ports are only classified by their switch
+// membership, which is unknown if the kernel can't list the ports of a switch.
+func Check(snap *mgmt.Snapshot, objTypes map[vpc.ObjType]bool) error {
+	if snap.PortsKnown() || !(objTypes[vpc.ObjTypeSwitch] || objTypes[vpc.ObjTypeSwitchPort]) {
+		return nil
+	}
+
+	return errors.New("unable to prune VPC Switches and VPC Switch Ports: the kernel can't list the ports of a VPC Switch")
+}
+
+// Select returns the candidates of the given object types that have been
+// orphaned for at least minAge as of now.
+func Select(candidates []Candidate, objTypes map[vpc.ObjType]bool, minAge time.Duration, now time.Time) []Candidate {
+	selected := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if objTypes[c.ObjType] && c.Age(now) >= minAge {
+			selected = append(selected, c)
+		}
+	}
+
+	return selected
+}
+
+// Forget removes a destroyed object from the ledger.
+func (l *Ledger) Forget(id vpc.ID) {
+	delete(l.FirstSeen, id)
+}
+
+// Save writes the ledger back to its path.
+func (l *Ledger) Save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	buf, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode orphan ledger")
+	}
+	buf = append(buf, '\n')
+
+	dir := filepath.Dir(l.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "unable to create orphan ledger directory %q", dir)
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(l.path))
+	if err != nil {
+		return errors.Wrapf(err, "unable to create orphan ledger %q", l.path)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "unable to write orphan ledger %q", l.path)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write orphan ledger %q", l.path)
+	}
+
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return errors.Wrapf(err, "unable to replace orphan ledger %q", l.path)
+	}
+
+	return nil
+}
+
+// Destroy destroys an orphan found in snap.  A port attached to a switch is
+// removed from its switch, every other object is destroyed.  A port whose
+// switch is unknown is left alone.
+func Destroy(orphan mgmt.Orphan, snap *mgmt.Snapshot) error {
+	switch orphan.ObjType {
+	case vpc.ObjTypeSwitchPort:
+		port, found := snap.Port(orphan.ID)
+		switch {
+		case found && port.Switch != (vpc.ID{}):
+			sw, err := vpcsw.Open(vpcsw.Config{ID: port.Switch, Writeable: true})
+			if err != nil {
+				return errors.Wrap(err, "unable to open VPC Switch")
+			}
+			defer sw.Close()
+
+			if err := sw.PortRemove(orphan.ID); err != nil {
+				return errors.Wrap(err, "unable to remove VPC Switch Port")
+			}
+
+			return nil
+		case !snap.PortsKnown():
+			return errors.Errorf("unable to find the VPC Switch of port %s", orphan.UnitName)
+		}
+
+		p, err := vpcp.Open(vpcp.Config{ID: orphan.ID, Writeable: true})
+		if err != nil {
+			return errors.Wrap(err, "unable to open VPC Switch Port")
+		}
+		defer p.Close()
+
+		return p.Destroy()
+	case vpc.ObjTypeLinkEth:
+		el, err := ethlink.Open(ethlink.Config{ID: orphan.ID, Writeable: true})
+		if err != nil {
+			return errors.Wrap(err, "unable to open VPC EthLink")
+		}
+		defer el.Close()
+
+		return el.Destroy()
+	case vpc.ObjTypeNICVM:
+		nic, err := vmnic.Open(vmnic.Config{ID: orphan.ID, Writeable: true})
+		if err != nil {
+			return errors.Wrap(err, "unable to open VM NIC")
+		}
+		defer nic.Close()
+
+		return nic.Destroy()
+	case vpc.ObjTypeSwitch:
+		sw, err := vpcsw.Open(vpcsw.Config{ID: orphan.ID, Writeable: true})
+		if err != nil {
+			return errors.Wrap(err, "unable to open VPC Switch")
+		}
+		defer sw.Close()
+
+		return sw.Destroy()
+	default:
+		return errors.Errorf("unable to prune VPC object type %s", orphan.ObjType)
+	}
+}
@@ -0,0 +1,322 @@
+package prune_test
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vmnic"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
+	"github.com/joyent/freebsd-vpc/internal/prune"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// useSimulator installs an empty Simulator for the duration of a test.
+func useSimulator(t *testing.T) {
+	prev := vpc.SetBackend(vpc.NewSimulator())
+	t.Cleanup(func() { vpc.SetBackend(prev) })
+}
+
+func snapshot(t *testing.T) *mgmt.Snapshot {
+	t.Helper()
+
+	mgr, err := mgmt.New(nil)
+	if err != nil {
+		t.Fatalf("unable to open VPC Management handle: %v", err)
+	}
+	defer mgr.Close()
+
+	snap, err := mgr.Snapshot()
+	if err != nil {
+		t.Fatalf("unable to snapshot VPC objects: %v", err)
+	}
+
+	return snap
+}
+
+// createSwitch creates and commits a switch with one port with nothing
+// connected and returns their IDs.
+func createSwitch(t *testing.T) (swID, portID vpc.ID) {
+	t.Helper()
+
+	swID = vpc.GenID(vpc.ObjTypeSwitch)
+	sw, err := vpcsw.Create(vpcsw.Config{ID: swID})
+	if err != nil {
+		t.Fatalf("unable to create switch: %v", err)
+	}
+	defer sw.Close()
+
+	portID = vpc.GenID(vpc.ObjTypeSwitchPort)
+	if err := sw.PortAdd(portID, nil); err != nil {
+		t.Fatalf("unable to add port: %v", err)
+	}
+
+	if err := sw.Commit(); err != nil {
+		t.Fatalf("unable to commit switch: %v", err)
+	}
+
+	return swID, portID
+}
+
+// createVMNIC creates and commits a VM NIC that is not connected to a port.
+func createVMNIC(t *testing.T) vpc.ID {
+	t.Helper()
+
+	id := vpc.GenID(vpc.ObjTypeNICVM)
+	nic, err := vmnic.Create(vmnic.Config{ID: id})
+	if err != nil {
+		t.Fatalf("unable to create VM NIC: %v", err)
+	}
+	defer nic.Close()
+
+	if err := nic.Commit(); err != nil {
+		t.Fatalf("unable to commit VM NIC: %v", err)
+	}
+
+	return id
+}
+
+// ages returns the age of each candidate as of now, keyed by ID.
+func ages(candidates []prune.Candidate, now time.Time) map[vpc.ID]time.Duration {
+	ages := make(map[vpc.ID]time.Duration, len(candidates))
+	for _, c := range candidates {
+		ages[c.ID] = c.Age(now)
+	}
+
+	return ages
+}
+
+func TestLedger_Observe(t *testing.T) {
+	useSimulator(t)
+	_, portID := createSwitch(t)
+	nicID := createVMNIC(t)
+
+	path := filepath.Join(t.TempDir(), "orphans.json")
+	ledger, err := prune.LoadLedger(path)
+	if err != nil {
+		t.Fatalf("unable to load ledger: %v", err)
+	}
+
+	t0 := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	orphans := snapshot(t).Orphans()
+	candidates := ledger.Observe(orphans, t0)
+	if len(candidates) != len(orphans) {
+		t.Fatalf("expected %d candidates, got %d", len(orphans), len(candidates))
+	}
+
+	for i, c := range candidates {
+		if c.ID != orphans[i].ID {
+			t.Errorf("candidate %d: expected %s, got %s", i, orphans[i].ID, c.ID)
+		}
+	}
+
+	want := map[vpc.ID]time.Duration{portID: 0, nicID: 0}
+	if diff := pretty.Compare(ages(candidates, t0), want); diff != "" {
+		t.Fatalf("ages diff: (-got +want)\n%s", diff)
+	}
+
+	if err := ledger.Save(); err != nil {
+		t.Fatalf("unable to save ledger: %v", err)
+	}
+
+	// Ages survive a reload of the ledger.
+	ledger, err = prune.LoadLedger(path)
+	if err != nil {
+		t.Fatalf("unable to reload ledger: %v", err)
+	}
+
+	t1 := t0.Add(time.Hour)
+	candidates = ledger.Observe(orphans, t1)
+	want = map[vpc.ID]time.Duration{portID: time.Hour, nicID: time.Hour}
+	if diff := pretty.Compare(ages(candidates, t1), want); diff != "" {
+		t.Fatalf("ages diff after reload: (-got +want)\n%s", diff)
+	}
+
+	// A forgotten object starts aging again when it is next seen, and objects
+	// that are no longer orphans are dropped.
+	ledger.Forget(nicID)
+	t2 := t1.Add(time.Hour)
+	candidates = ledger.Observe(orphans[:1], t2)
+	if diff := pretty.Compare(ages(candidates, t2), map[vpc.ID]time.Duration{portID: 2 * time.Hour}); diff != "" {
+		t.Fatalf("ages diff after forget: (-got +want)\n%s", diff)
+	}
+
+	if _, found := ledger.FirstSeen[nicID]; found {
+		t.Fatalf("forgotten VM NIC still in the ledger")
+	}
+
+	candidates = ledger.Observe(orphans, t2)
+	if diff := pretty.Compare(ages(candidates, t2), map[vpc.ID]time.Duration{portID: 2 * time.Hour, nicID: 0}); diff != "" {
+		t.Fatalf("ages diff after the VM NIC reappeared: (-got +want)\n%s", diff)
+	}
+
+	// A first-seen time in the future, e.g. after the clock was set back, is
+	// reset.
+	candidates = ledger.Observe(orphans, t0)
+	if diff := pretty.Compare(ages(candidates, t0), map[vpc.ID]time.Duration{portID: 0, nicID: 0}); diff != "" {
+		t.Fatalf("ages diff after the clock was set back: (-got +want)\n%s", diff)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	now := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+	candidate := func(objType vpc.ObjType, age time.Duration) prune.Candidate {
+		var c prune.Candidate
+		c.ID = vpc.GenID(objType)
+		c.ObjType = objType
+		c.FirstSeen = now.Add(-age)
+		return c
+	}
+
+	port := candidate(vpc.ObjTypeSwitchPort, 2*time.Hour)
+	nic := candidate(vpc.ObjTypeNICVM, 30*time.Minute)
+	sw := candidate(vpc.ObjTypeSwitch, time.Hour)
+	candidates := []prune.Candidate{port, nic, sw}
+
+	all := map[vpc.ObjType]bool{
+		vpc.ObjTypeSwitchPort: true,
+		vpc.ObjTypeNICVM:      true,
+		vpc.ObjTypeSwitch:     true,
+	}
+
+	tests := []struct {
+		name     string
+		objTypes map[vpc.ObjType]bool
+		minAge   time.Duration
+		want     []prune.Candidate
+	}{
+		{"all", all, 0, []prune.Candidate{port, nic, sw}},
+		{"min-age", all, time.Hour, []prune.Candidate{port, sw}},
+		{"obj-type", map[vpc.ObjType]bool{vpc.ObjTypeNICVM: true, vpc.ObjTypeSwitch: true}, 0, []prune.Candidate{nic, sw}},
+		{"obj-type and min-age", map[vpc.ObjType]bool{vpc.ObjTypeNICVM: true, vpc.ObjTypeSwitch: true}, time.Hour, []prune.Candidate{sw}},
+		{"none", map[vpc.ObjType]bool{}, 0, []prune.Candidate{}},
+	}
+
+	for _, test := range tests {
+		got := prune.Select(candidates, test.objTypes, test.minAge, now)
+		if diff := pretty.Compare(got, test.want); diff != "" {
+			t.Errorf("%s: selected diff: (-got +want)\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	useSimulator(t)
+	swID, portID := createSwitch(t)
+	nicID := createVMNIC(t)
+
+	snap := snapshot(t)
+	orphans := make(map[vpc.ID]mgmt.Orphan)
+	for _, orphan := range snap.Orphans() {
+		orphans[orphan.ID] = orphan
+	}
+
+	// The port is attached to the switch and is removed through it.
+	if err := prune.Destroy(orphans[portID], snap); err != nil {
+		t.Fatalf("unable to destroy port: %v", err)
+	}
+
+	if err := prune.Destroy(orphans[nicID], snap); err != nil {
+		t.Fatalf("unable to destroy VM NIC: %v", err)
+	}
+
+	snap = snapshot(t)
+	if _, found := snap.Port(portID); found {
+		t.Fatalf("port not destroyed")
+	}
+
+	if _, found := snap.VMNIC(nicID); found {
+		t.Fatalf("VM NIC not destroyed")
+	}
+
+	sw, found := snap.Switch(swID)
+	switch {
+	case !found:
+		t.Fatalf("switch destroyed with its port")
+	case len(sw.Ports) != 0:
+		t.Fatalf("port still attached to the switch: %v", sw.Ports)
+	}
+
+	// The switch without ports is an orphan for the next prune.
+	remaining := snap.Orphans()
+	if len(remaining) != 1 || remaining[0].ID != swID {
+		t.Fatalf("expected only the switch to be orphaned, got %+v", remaining)
+	}
+
+	if err := prune.Destroy(remaining[0], snap); err != nil {
+		t.Fatalf("unable to destroy switch: %v", err)
+	}
+
+	if _, found := snapshot(t).Switch(swID); found {
+		t.Fatalf("switch not destroyed")
+	}
+}
+
+func TestCheck_PortsUnknown(t *testing.T) {
+	useSimulator(t)
+	swID, portID := createSwitch(t)
+	nicID := createVMNIC(t)
+
+	// The kernel can't list the ports of a switch: neither the switch nor its
+	// port can be told apart from orphans.
+	faults := vpc.NewFaultInjector()
+	faults.Inject(vpc.Fault{ObjType: vpc.ObjTypeSwitch, Op: vpc.Op(9), Errno: syscall.EOPNOTSUPP})
+	prev := vpc.SetInterceptors(faults.Interceptor())
+	snap := snapshot(t)
+	vpc.SetInterceptors(prev...)
+
+	now := time.Now()
+	ledger, err := prune.LoadLedger("")
+	if err != nil {
+		t.Fatalf("unable to load ledger: %v", err)
+	}
+	candidates := ledger.Observe(snap.Orphans(), now)
+
+	all := map[vpc.ObjType]bool{
+		vpc.ObjTypeSwitchPort: true,
+		vpc.ObjTypeLinkEth:    true,
+		vpc.ObjTypeNICVM:      true,
+		vpc.ObjTypeSwitch:     true,
+	}
+	var selected []vpc.ID
+	for _, c := range prune.Select(candidates, all, 0, now) {
+		selected = append(selected, c.ID)
+	}
+	if diff := pretty.Compare(selected, []vpc.ID{nicID}); diff != "" {
+		t.Fatalf("selected diff: (-got +want)\n%s", diff)
+	}
+
+	switchTypes := map[vpc.ObjType]bool{vpc.ObjTypeSwitchPort: true, vpc.ObjTypeSwitch: true}
+	if got := prune.Select(candidates, switchTypes, 0, now); len(got) != 0 {
+		t.Fatalf("switch or port selected: %+v", got)
+	}
+
+	for _, objTypes := range []map[vpc.ObjType]bool{all, switchTypes, {vpc.ObjTypeSwitch: true}} {
+		if err := prune.Check(snap, objTypes); err == nil || !strings.Contains(err.Error(), "can't list the ports") {
+			t.Errorf("%v: expected an error, got %v", objTypes, err)
+		}
+	}
+
+	if err := prune.Check(snap, map[vpc.ObjType]bool{vpc.ObjTypeNICVM: true, vpc.ObjTypeLinkEth: true}); err != nil {
+		t.Errorf("unable to prune VM NICs and EthLinks: %v", err)
+	}
+
+	// Destroy refuses to guess the switch of a port.
+	port, _ := snap.Port(portID)
+	if err := prune.Destroy(mgmt.Orphan{Object: port.Object}, snap); err == nil {
+		t.Fatalf("port of an unknown switch destroyed")
+	}
+
+	snap = snapshot(t)
+	if _, found := snap.Port(portID); !found {
+		t.Fatalf("port destroyed")
+	}
+
+	if _, found := snap.Switch(swID); !found {
+		t.Fatalf("switch destroyed")
+	}
+}
@@ -0,0 +1,785 @@
+package topology
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/ethlink"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vmnic"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcp"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/vpcsw"
+	"github.com/joyent/freebsd-vpc/internal/command"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Op is the kind of change made by an Action.
+type Op int
+
+const (
+	OpCreate Op = iota
+	OpModify
+	OpDelete
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpCreate:
+		return "create"
+	case OpModify:
+		return "modify"
+	case OpDelete:
+		return "delete"
+	default:
+		return "invalid"
+	}
+}
+
+// Action is a single change to the VPC objects in the kernel.
+type Action struct {
+	Op   Op
+	Kind string
+
+	// Name is the name of the object in the topology file, or its unit name
+	// for objects that are not part of the topology file.
+	Name string
+	ID   vpc.ID
+
+	// Detail describes the change, e.g. "vni 0 -> 123".
+	Detail string
+
+	// Existing is true if the action changes or removes an object that
+	// already exists in the kernel, i.e. the kernel state drifted from the
+	// Topology.
+	Existing bool
+
+	run func(a *_Applier) error
+}
+
+func (act Action) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("op", act.Op.String()).
+		Str("kind", act.Kind).
+		Str("name", act.Name).
+		Str("id", act.ID.String()).
+		Str("detail", act.Detail)
+}
+
+// Values returns the fields of the Action rendered by Plan.Table.
+func (act Action) Values() []interface{} {
+	return []interface{}{act.Op, act.Kind, act.Name, act.ID, act.Detail}
+}
+
+// Plan is the ordered list of Actions that converge the kernel state onto a
+// Topology.
+type Plan struct {
+	Actions []Action
+}
+
+// Empty returns true if the kernel state already matches the Topology.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+var _PlanColumns = []command.Column{
+	{Name: "action", Align: tablewriter.ALIGN_LEFT},
+	{Name: "kind", Align: tablewriter.ALIGN_LEFT},
+	{Name: "name", Align: tablewriter.ALIGN_LEFT},
+	{Name: "id", Align: tablewriter.ALIGN_LEFT},
+	{Name: "detail", Align: tablewriter.ALIGN_LEFT},
+}
+
+// Table returns the actions of the plan for rendering.
+func (p *Plan) Table() command.Table {
+	records := make([]command.Record, len(p.Actions))
+	for i := range p.Actions {
+		records[i] = p.Actions[i]
+	}
+
+	return command.Table{
+		Name:    "actions",
+		Columns: _PlanColumns,
+		Records: records,
+		Total:   true,
+	}
+}
+
+// SkipExisting removes the actions that change or remove existing objects from
+// the plan and returns them.  The remaining actions only create the missing
+// objects and connect the ports created by the plan.
+func (p *Plan) SkipExisting() []Action {
+	var kept, skipped []Action
+	for _, act := range p.Actions {
+		if act.Existing {
+			skipped = append(skipped, act)
+		} else {
+			kept = append(kept, act)
+		}
+	}
+	p.Actions = kept
+
+	return skipped
+}
+
+func (p *Plan) add(act Action) {
+	p.Actions = append(p.Actions, act)
+}
+
+// existing marks an action that changes or removes an existing object.
+func existing(act Action) Action {
+	act.Existing = true
+	return act
+}
+
+// Apply runs the actions of the plan in order.  Objects created by the plan are
+// only committed once every action succeeded.  If an action fails, the changes
+// made so far are undone in reverse order, except for removed ports and
+// destroyed objects which can not be restored.  progress is called before each
+// action.
+func (p *Plan) Apply(progress func(Action)) (err error) {
+	a := &_Applier{
+		txn:      vpc.NewTxn(),
+		switches: make(map[vpc.ID]*vpcsw.VPCSW),
+		ports:    make(map[vpc.ID]*vpcp.VPCP),
+		vmnics:   make(map[vpc.ID]*vmnic.VMNIC),
+		ethLinks: make(map[vpc.ID]*ethlink.EthLink),
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if rbErr := a.txn.Rollback(); rbErr != nil {
+			err = errors.Wrapf(err, "unable to undo changes: %v", rbErr)
+		}
+	}()
+
+	for _, act := range p.Actions {
+		if progress != nil {
+			progress(act)
+		}
+
+		if err := act.run(a); err != nil {
+			return errors.Wrapf(err, "unable to %s %s %q", act.Op, act.Kind, act.Name)
+		}
+	}
+
+	if err := a.txn.Commit(); err != nil {
+		return errors.Wrap(err, "unable to commit changes")
+	}
+
+	return nil
+}
+
+// NewPlan returns the Plan that converges the kernel state described by snap
+// onto t.  Objects that are not part of t are left alone, except for the ports
+// of the switches in t, which are removed.
+func NewPlan(t *Topology, snap *mgmt.Snapshot) (*Plan, error) {
+	if err := checkPortsKnown(t, snap); err != nil {
+		return nil, err
+	}
+
+	p := &Plan{}
+
+	for _, sw := range t.Switches {
+		live, found := snap.Switch(sw.id)
+		switch {
+		case !found:
+			p.add(createSwitch(sw))
+		case !bytes.Equal(live.MAC, sw.mac):
+			p.add(existing(setSwitchMAC(sw, live.MAC)))
+		}
+	}
+
+	for _, nic := range t.VMNICs {
+		live, found := snap.VMNIC(nic.id)
+		if !found {
+			p.add(createVMNIC(nic))
+			continue
+		}
+
+		if !bytes.Equal(live.MAC, nic.mac) {
+			p.add(existing(setVMNICMAC(nic, live.MAC)))
+		}
+
+		if nic.MTU != 0 && uint32(nic.MTU) != live.MTU {
+			p.add(existing(setVMNICMTU(nic, live.MTU)))
+		}
+
+		if nic.NQueues != 0 && uint16(nic.NQueues) != live.NQueues {
+			p.add(existing(setVMNICNQueues(nic, live.NQueues)))
+		}
+	}
+
+	for _, el := range t.EthLinks {
+		live, found := snap.EthLink(el.id)
+		switch {
+		case !found:
+			p.add(createEthLink(el))
+		case live.L2Name != "" && live.L2Name != el.L2Name:
+			return nil, errors.Errorf("ethlink %q is attached to %q instead of %q: destroy it first", el.Name, live.L2Name, el.L2Name)
+		}
+	}
+
+	// Disconnect ports from the wrong peer and remove unknown ports before
+	// adding ports and connecting them.
+	var adds, connects []Action
+	for _, sw := range t.Switches {
+		want := make(map[vpc.ID]bool, len(sw.Ports))
+		for _, port := range sw.Ports {
+			want[port.id] = true
+
+			live, found := snap.Port(port.id)
+			switch {
+			case !found:
+				adds = append(adds, addPort(sw, port))
+				if port.peer != (vpc.ID{}) {
+					connects = append(connects, connectPort(port))
+				}
+				continue
+			case live.Switch != sw.id:
+				return nil, errors.Errorf("switch %q port %q belongs to switch %s", sw.Name, port.Name, live.Switch)
+			case live.Uplink && !port.Uplink:
+				return nil, errors.Errorf("switch %q port %q is an uplink port: remove it first", sw.Name, port.Name)
+			}
+
+			if port.Uplink && !live.Uplink {
+				p.add(existing(setPortUplink(sw, port)))
+			}
+
+			if live.VNI != port.vni {
+				p.add(existing(setPortVNI(port, live.VNI)))
+			}
+
+			if live.Peer != port.peer {
+				if live.Peer != (vpc.ID{}) {
+					p.add(existing(disconnectPort(port.Name, port.id, live.Peer)))
+				}
+
+				if port.peer != (vpc.ID{}) {
+					connects = append(connects, existing(connectPort(port)))
+				}
+			}
+		}
+
+		live, found := snap.Switch(sw.id)
+		if !found {
+			continue
+		}
+
+		for _, portID := range live.Ports {
+			if want[portID] {
+				continue
+			}
+
+			livePort, found := snap.Port(portID)
+			if !found {
+				continue
+			}
+
+			if livePort.Peer != (vpc.ID{}) {
+				p.add(existing(disconnectPort(livePort.UnitName, portID, livePort.Peer)))
+			}
+			p.add(existing(removePort(sw.id, livePort.UnitName, portID)))
+		}
+	}
+
+	p.Actions = append(p.Actions, adds...)
+	p.Actions = append(p.Actions, connects...)
+
+	return p, nil
+}
+
+// NewDestroyPlan returns the Plan that removes every object of t that exists in
+// the kernel state described by snap, in the reverse order of their creation.
+func NewDestroyPlan(t *Topology, snap *mgmt.Snapshot) (*Plan, error) {
+	if err := checkPortsKnown(t, snap); err != nil {
+		return nil, err
+	}
+
+	p := &Plan{}
+
+	for _, sw := range t.Switches {
+		for _, port := range sw.Ports {
+			if live, found := snap.Port(port.id); found && live.Peer != (vpc.ID{}) {
+				p.add(disconnectPort(port.Name, port.id, live.Peer))
+			}
+		}
+	}
+
+	for _, el := range t.EthLinks {
+		if _, found := snap.EthLink(el.id); found {
+			p.add(destroyEthLink(el))
+		}
+	}
+
+	for _, nic := range t.VMNICs {
+		if _, found := snap.VMNIC(nic.id); found {
+			p.add(destroyVMNIC(nic))
+		}
+	}
+
+	for _, sw := range t.Switches {
+		liveSwitch, found := snap.Switch(sw.id)
+		if !found {
+			continue
+		}
+
+		for _, port := range sw.Ports {
+			if live, found := snap.Port(port.id); found && live.Switch == sw.id {
+				p.add(removePort(sw.id, port.Name, port.id))
+			}
+		}
+
+		p.add(destroySwitch(sw.Name, liveSwitch.ID))
+	}
+
+	return p, nil
+}
+
+// checkPortsKnown returns an error if t has switches and snap doesn't know
+// which switch each port is attached to.
+func checkPortsKnown(t *Topology, snap *mgmt.Snapshot) error {
+	if len(t.Switches) == 0 || snap.PortsKnown() {
+		return nil
+	}
+
+	return errors.New("the kernel can't list the ports of a VPC Switch")
+}
+
+// _Applier holds the handles used while applying a Plan.
+type _Applier struct {
+	txn      *vpc.Txn
+	switches map[vpc.ID]*vpcsw.VPCSW
+	ports    map[vpc.ID]*vpcp.VPCP
+	vmnics   map[vpc.ID]*vmnic.VMNIC
+	ethLinks map[vpc.ID]*ethlink.EthLink
+}
+
+func (a *_Applier) vpcSwitch(id vpc.ID) (*vpcsw.VPCSW, error) {
+	if sw, found := a.switches[id]; found {
+		return sw, nil
+	}
+
+	sw, err := vpcsw.Open(vpcsw.Config{ID: id, Writeable: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open VPC Switch")
+	}
+	a.txn.Opened("VPC Switch", sw)
+	a.switches[id] = sw
+
+	return sw, nil
+}
+
+func (a *_Applier) vpcPort(id vpc.ID) (*vpcp.VPCP, error) {
+	if port, found := a.ports[id]; found {
+		return port, nil
+	}
+
+	port, err := vpcp.Open(vpcp.Config{ID: id, Writeable: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open VPC Switch Port")
+	}
+	a.txn.Opened("VPC Switch Port", port)
+	a.ports[id] = port
+
+	return port, nil
+}
+
+func (a *_Applier) vmNIC(id vpc.ID) (*vmnic.VMNIC, error) {
+	if nic, found := a.vmnics[id]; found {
+		return nic, nil
+	}
+
+	nic, err := vmnic.Open(vmnic.Config{ID: id, Writeable: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open VM NIC")
+	}
+	a.txn.Opened("VM NIC", nic)
+	a.vmnics[id] = nic
+
+	return nic, nil
+}
+
+func (a *_Applier) ethLink(id vpc.ID) (*ethlink.EthLink, error) {
+	if el, found := a.ethLinks[id]; found {
+		return el, nil
+	}
+
+	el, err := ethlink.Open(ethlink.Config{ID: id, Writeable: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open VPC EthLink")
+	}
+	a.txn.Opened("VPC EthLink", el)
+	a.ethLinks[id] = el
+
+	return el, nil
+}
+
+func createSwitch(sw *Switch) Action {
+	return Action{
+		Op:     OpCreate,
+		Kind:   "switch",
+		Name:   sw.Name,
+		ID:     sw.id,
+		Detail: fmt.Sprintf("vni %d, mac %s", sw.VNI, sw.mac),
+		run: func(a *_Applier) error {
+			s, err := vpcsw.Create(vpcsw.Config{ID: sw.id, MAC: sw.mac, VNI: vpc.VNI(sw.VNI)})
+			if err != nil {
+				return errors.Wrap(err, "unable to create VPC Switch")
+			}
+			a.txn.Created("VPC Switch", s)
+			a.switches[sw.id] = s
+
+			return nil
+		},
+	}
+}
+
+func setSwitchMAC(sw *Switch, old net.HardwareAddr) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "switch",
+		Name:   sw.Name,
+		ID:     sw.id,
+		Detail: fmt.Sprintf("mac %s -> %s", old, sw.mac),
+		run: func(a *_Applier) error {
+			s, err := a.vpcSwitch(sw.id)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("set VPC Switch MAC address",
+				func() error { return s.SetMAC(sw.mac) },
+				func() error { return s.SetMAC(old) },
+			)
+		},
+	}
+}
+
+func createVMNIC(nic *VMNIC) Action {
+	detail := fmt.Sprintf("mac %s", nic.mac)
+	if nic.MTU != 0 {
+		detail += fmt.Sprintf(", mtu %d", nic.MTU)
+	}
+	if nic.NQueues != 0 {
+		detail += fmt.Sprintf(", num-queues %d", nic.NQueues)
+	}
+
+	return Action{
+		Op:     OpCreate,
+		Kind:   "vmnic",
+		Name:   nic.Name,
+		ID:     nic.id,
+		Detail: detail,
+		run: func(a *_Applier) error {
+			n, err := vmnic.Create(vmnic.Config{ID: nic.id, MAC: nic.mac})
+			if err != nil {
+				return errors.Wrap(err, "unable to create VM NIC")
+			}
+			a.txn.Created("VM NIC", n)
+			a.vmnics[nic.id] = n
+
+			if nic.MTU != 0 {
+				if err := n.SetMTU(uint32(nic.MTU)); err != nil {
+					return errors.Wrap(err, "unable to set VM NIC MTU")
+				}
+			}
+
+			if nic.NQueues != 0 {
+				if err := n.NQueuesSet(uint16(nic.NQueues)); err != nil {
+					return errors.Wrap(err, "unable to set the number of hardware queues")
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func setVMNICMAC(nic *VMNIC, old net.HardwareAddr) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "vmnic",
+		Name:   nic.Name,
+		ID:     nic.id,
+		Detail: fmt.Sprintf("mac %s -> %s", old, nic.mac),
+		run: func(a *_Applier) error {
+			n, err := a.vmNIC(nic.id)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("set VM NIC MAC address",
+				func() error { return n.SetMAC(nic.mac) },
+				func() error { return n.SetMAC(old) },
+			)
+		},
+	}
+}
+
+func setVMNICMTU(nic *VMNIC, old uint32) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "vmnic",
+		Name:   nic.Name,
+		ID:     nic.id,
+		Detail: fmt.Sprintf("mtu %d -> %d", old, nic.MTU),
+		run: func(a *_Applier) error {
+			n, err := a.vmNIC(nic.id)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("set VM NIC MTU",
+				func() error { return n.SetMTU(uint32(nic.MTU)) },
+				func() error { return n.SetMTU(old) },
+			)
+		},
+	}
+}
+
+func setVMNICNQueues(nic *VMNIC, old uint16) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "vmnic",
+		Name:   nic.Name,
+		ID:     nic.id,
+		Detail: fmt.Sprintf("num-queues %d -> %d", old, nic.NQueues),
+		run: func(a *_Applier) error {
+			n, err := a.vmNIC(nic.id)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("set VM NIC hardware queues",
+				func() error { return n.NQueuesSet(uint16(nic.NQueues)) },
+				func() error { return n.NQueuesSet(old) },
+			)
+		},
+	}
+}
+
+func destroyVMNIC(nic *VMNIC) Action {
+	return Action{
+		Op:   OpDelete,
+		Kind: "vmnic",
+		Name: nic.Name,
+		ID:   nic.id,
+		run: func(a *_Applier) error {
+			n, err := a.vmNIC(nic.id)
+			if err != nil {
+				return err
+			}
+
+			return n.Destroy()
+		},
+	}
+}
+
+func createEthLink(el *EthLink) Action {
+	return Action{
+		Op:     OpCreate,
+		Kind:   "ethlink",
+		Name:   el.Name,
+		ID:     el.id,
+		Detail: fmt.Sprintf("l2-name %s", el.L2Name),
+		run: func(a *_Applier) error {
+			l, err := ethlink.Create(ethlink.Config{ID: el.id, Name: el.L2Name})
+			if err != nil {
+				return errors.Wrap(err, "unable to create VPC EthLink")
+			}
+			a.txn.Created("VPC EthLink", l)
+			a.ethLinks[el.id] = l
+
+			// Attaching is undone by destroying the uncommitted EthLink.
+			if err := l.Attach(); err != nil {
+				return errors.Wrapf(err, "unable to attach L2 link to device %q", el.L2Name)
+			}
+
+			return nil
+		},
+	}
+}
+
+func destroyEthLink(el *EthLink) Action {
+	return Action{
+		Op:   OpDelete,
+		Kind: "ethlink",
+		Name: el.Name,
+		ID:   el.id,
+		run: func(a *_Applier) error {
+			l, err := a.ethLink(el.id)
+			if err != nil {
+				return err
+			}
+
+			return l.Destroy()
+		},
+	}
+}
+
+func addPort(sw *Switch, port *Port) Action {
+	detail := fmt.Sprintf("switch %s, vni %d", sw.Name, port.vni)
+	if port.Uplink {
+		detail += ", uplink"
+	}
+
+	return Action{
+		Op:     OpCreate,
+		Kind:   "port",
+		Name:   port.Name,
+		ID:     port.id,
+		Detail: detail,
+		run: func(a *_Applier) error {
+			s, err := a.vpcSwitch(sw.id)
+			if err != nil {
+				return err
+			}
+
+			add := func() error { return s.PortAdd(port.id, nil) }
+			if port.Uplink {
+				add = func() error { return s.PortUplinkSet(port.id, nil) }
+			}
+
+			err = a.txn.Do("add VPC Switch Port", add,
+				func() error { return s.PortRemove(port.id) },
+			)
+			if err != nil {
+				return err
+			}
+
+			p, err := a.vpcPort(port.id)
+			if err != nil {
+				return err
+			}
+
+			return p.SetVNI(port.vni)
+		},
+	}
+}
+
+func setPortUplink(sw *Switch, port *Port) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "port",
+		Name:   port.Name,
+		ID:     port.id,
+		Detail: "uplink false -> true",
+		run: func(a *_Applier) error {
+			s, err := a.vpcSwitch(sw.id)
+			if err != nil {
+				return err
+			}
+
+			return s.PortUplinkSet(port.id, nil)
+		},
+	}
+}
+
+func setPortVNI(port *Port, old vpc.VNI) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "port",
+		Name:   port.Name,
+		ID:     port.id,
+		Detail: fmt.Sprintf("vni %d -> %d", old, port.vni),
+		run: func(a *_Applier) error {
+			p, err := a.vpcPort(port.id)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("set VPC Switch Port VNI",
+				func() error { return p.SetVNI(port.vni) },
+				func() error { return p.SetVNI(old) },
+			)
+		},
+	}
+}
+
+func connectPort(port *Port) Action {
+	peer := port.VMNIC
+	if peer == "" {
+		peer = port.EthLink
+	}
+
+	return Action{
+		Op:     OpModify,
+		Kind:   "port",
+		Name:   port.Name,
+		ID:     port.id,
+		Detail: fmt.Sprintf("connect %s", peer),
+		run: func(a *_Applier) error {
+			p, err := a.vpcPort(port.id)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("connect VPC Switch Port",
+				func() error { return p.Connect(port.peer) },
+				func() error { return p.Disconnect(port.peer) },
+			)
+		},
+	}
+}
+
+func disconnectPort(name string, portID, peer vpc.ID) Action {
+	return Action{
+		Op:     OpModify,
+		Kind:   "port",
+		Name:   name,
+		ID:     portID,
+		Detail: fmt.Sprintf("disconnect %s", peer),
+		run: func(a *_Applier) error {
+			p, err := a.vpcPort(portID)
+			if err != nil {
+				return err
+			}
+
+			return a.txn.Do("disconnect VPC Switch Port",
+				func() error { return p.Disconnect(peer) },
+				func() error { return p.Connect(peer) },
+			)
+		},
+	}
+}
+
+func removePort(switchID vpc.ID, name string, portID vpc.ID) Action {
+	return Action{
+		Op:   OpDelete,
+		Kind: "port",
+		Name: name,
+		ID:   portID,
+		run: func(a *_Applier) error {
+			s, err := a.vpcSwitch(switchID)
+			if err != nil {
+				return err
+			}
+
+			// A removed port can not be restored with its configuration, so
+			// there is no undo.
+			return s.PortRemove(portID)
+		},
+	}
+}
+
+func destroySwitch(name string, id vpc.ID) Action {
+	return Action{
+		Op:   OpDelete,
+		Kind: "switch",
+		Name: name,
+		ID:   id,
+		run: func(a *_Applier) error {
+			s, err := a.vpcSwitch(id)
+			if err != nil {
+				return err
+			}
+
+			return s.Destroy()
+		},
+	}
+}
@@ -0,0 +1,267 @@
+package topology
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// useSimulator installs an empty Simulator for the duration of a test.
+func useSimulator(t *testing.T) {
+	prev := vpc.SetBackend(vpc.NewSimulator())
+	t.Cleanup(func() { vpc.SetBackend(prev) })
+}
+
+func snapshot(t *testing.T) *mgmt.Snapshot {
+	t.Helper()
+
+	mgr, err := mgmt.New(nil)
+	if err != nil {
+		t.Fatalf("unable to open VPC Management handle: %v", err)
+	}
+	defer mgr.Close()
+
+	snap, err := mgr.Snapshot()
+	if err != nil {
+		t.Fatalf("unable to snapshot VPC objects: %v", err)
+	}
+
+	return snap
+}
+
+// summarize returns the op, kind, name, and the first word of the detail of
+// each action of p.
+func summarize(p *Plan) []string {
+	summary := make([]string, len(p.Actions))
+	for i, act := range p.Actions {
+		summary[i] = act.Op.String() + " " + act.Kind + " " + act.Name
+		if fields := strings.Fields(act.Detail); act.Op == OpModify && len(fields) > 0 {
+			summary[i] += " " + fields[0]
+		}
+	}
+
+	return summary
+}
+
+func apply(t *testing.T, topo *Topology) {
+	t.Helper()
+
+	p, err := NewPlan(topo, snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	if err := p.Apply(nil); err != nil {
+		t.Fatalf("unable to apply: %v", err)
+	}
+}
+
+func TestNewPlan_EmptyHost(t *testing.T) {
+	useSimulator(t)
+	topo := testTopology(t)
+
+	p, err := NewPlan(topo, snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	want := []string{
+		"create switch sw0",
+		"create vmnic vm0",
+		"create ethlink uplink",
+		"create port p-vm0",
+		"create port p-uplink",
+		"modify port p-vm0 connect",
+		"modify port p-uplink connect",
+	}
+	if diff := pretty.Compare(summarize(p), want); diff != "" {
+		t.Fatalf("plan diff: (-got +want)\n%s", diff)
+	}
+
+	for _, act := range p.Actions {
+		if act.Existing {
+			t.Errorf("action %q on an empty host marked as existing", act.Name)
+		}
+	}
+
+	if err := p.Apply(nil); err != nil {
+		t.Fatalf("unable to apply: %v", err)
+	}
+
+	// Applying converges: there is nothing left to do.
+	again, err := NewPlan(topo, snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	if !again.Empty() {
+		t.Fatalf("plan not empty after apply: %v", summarize(again))
+	}
+}
+
+func TestNewPlan_PartlyBuilt(t *testing.T) {
+	useSimulator(t)
+	topo := testTopology(t)
+
+	// Build the switch with its vmnic port only, and let the kernel state
+	// drift: a different vmnic MTU, a different port VNI, and an extra port.
+	partial := testTopology(t)
+	partial.Switches[0].ID = topo.Switches[0].ID
+	partial.Switches[0].Ports = partial.Switches[0].Ports[:1]
+	partial.Switches[0].Ports[0].ID = topo.Switches[0].Ports[0].ID
+	partial.Switches[0].Ports[0].VNI = 200
+	partial.Switches[0].Ports = append(partial.Switches[0].Ports, &Port{
+		Name: "extra",
+		ID:   vpc.GenID(vpc.ObjTypeSwitchPort).String(),
+	})
+	partial.VMNICs[0].ID = topo.VMNICs[0].ID
+	partial.VMNICs[0].MTU = 1500
+	partial.EthLinks = nil
+	if err := partial.validate(); err != nil {
+		t.Fatalf("invalid partial topology: %v", err)
+	}
+	apply(t, partial)
+
+	snap := snapshot(t)
+	extra, found := snap.Port(partial.Switches[0].Ports[1].id)
+	if !found {
+		t.Fatalf("extra port not created")
+	}
+
+	p, err := NewPlan(topo, snap)
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	want := []string{
+		"modify vmnic vm0 mtu",
+		"create ethlink uplink",
+		"modify port p-vm0 vni",
+		"delete port " + extra.UnitName,
+		"create port p-uplink",
+		"modify port p-uplink connect",
+	}
+	if diff := pretty.Compare(summarize(p), want); diff != "" {
+		t.Fatalf("plan diff: (-got +want)\n%s", diff)
+	}
+
+	var existing []string
+	for _, act := range p.SkipExisting() {
+		existing = append(existing, act.Name)
+	}
+	if diff := pretty.Compare(existing, []string{"vm0", "p-vm0", extra.UnitName}); diff != "" {
+		t.Fatalf("existing actions diff: (-got +want)\n%s", diff)
+	}
+}
+
+func TestPlan_ApplyRollback(t *testing.T) {
+	useSimulator(t)
+	topo := testTopology(t)
+
+	p, err := NewPlan(topo, snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	// Fail the second connect: every object has been created and the first
+	// port has been connected by then.
+	faults := vpc.NewFaultInjector()
+	faults.Inject(vpc.Fault{ObjType: vpc.ObjTypeSwitchPort, Op: vpc.Op(1), Errno: syscall.ENXIO})
+	defer vpc.SetInterceptors(vpc.Interceptors()...)
+
+	var ran []string
+	err = p.Apply(func(act Action) {
+		ran = append(ran, act.Name)
+		if len(ran) == len(p.Actions) {
+			vpc.SetInterceptors(faults.Interceptor())
+		}
+	})
+	if err == nil {
+		t.Fatalf("apply should have failed")
+	}
+
+	if !strings.Contains(err.Error(), `unable to modify port "p-uplink"`) {
+		t.Fatalf("unexpected apply error: %v", err)
+	}
+
+	if len(ran) != len(p.Actions) {
+		t.Fatalf("apply stopped after %d of %d actions", len(ran), len(p.Actions))
+	}
+
+	snap := snapshot(t)
+	if n := len(snap.Switches) + len(snap.Ports) + len(snap.VMNICs) + len(snap.EthLinks); n != 0 {
+		t.Fatalf("%d objects left behind by the rollback", n)
+	}
+}
+
+func TestNewDestroyPlan(t *testing.T) {
+	useSimulator(t)
+	topo := testTopology(t)
+
+	p, err := NewDestroyPlan(topo, snapshot(t))
+	switch {
+	case err != nil:
+		t.Fatalf("unable to plan: %v", err)
+	case !p.Empty():
+		t.Fatalf("destroy plan not empty on an empty host: %v", summarize(p))
+	}
+
+	apply(t, topo)
+
+	p, err = NewDestroyPlan(topo, snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	want := []string{
+		"modify port p-vm0 disconnect",
+		"modify port p-uplink disconnect",
+		"delete ethlink uplink",
+		"delete vmnic vm0",
+		"delete port p-vm0",
+		"delete port p-uplink",
+		"delete switch sw0",
+	}
+	if diff := pretty.Compare(summarize(p), want); diff != "" {
+		t.Fatalf("destroy plan diff: (-got +want)\n%s", diff)
+	}
+
+	if err := p.Apply(nil); err != nil {
+		t.Fatalf("unable to destroy: %v", err)
+	}
+
+	snap := snapshot(t)
+	if n := len(snap.Switches) + len(snap.Ports) + len(snap.VMNICs) + len(snap.EthLinks); n != 0 {
+		t.Fatalf("%d objects left behind by destroy", n)
+	}
+}
+
+func TestNewPlan_PortsUnknown(t *testing.T) {
+	useSimulator(t)
+	topo := testTopology(t)
+	apply(t, topo)
+
+	faults := vpc.NewFaultInjector()
+	faults.Inject(vpc.Fault{ObjType: vpc.ObjTypeSwitch, Op: vpc.Op(9), Errno: syscall.EOPNOTSUPP})
+	prev := vpc.SetInterceptors(faults.Interceptor())
+	snap := snapshot(t)
+	vpc.SetInterceptors(prev...)
+
+	if _, err := NewPlan(topo, snap); err == nil || !strings.Contains(err.Error(), "can't list the ports") {
+		t.Errorf("expected an error from NewPlan, got %v", err)
+	}
+
+	if _, err := NewDestroyPlan(topo, snap); err == nil || !strings.Contains(err.Error(), "can't list the ports") {
+		t.Errorf("expected an error from NewDestroyPlan, got %v", err)
+	}
+
+	// Topologies without switches don't depend on the ports of a switch.
+	nicOnly := &Topology{VMNICs: topo.VMNICs}
+	if p, err := NewPlan(nicOnly, snap); err != nil || !p.Empty() {
+		t.Errorf("unexpected plan for a topology without switches: %v, %v", err, p)
+	}
+}
@@ -0,0 +1,224 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc/mgmt"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// StateVersion is the version of the state files written by Export.  State
+// files written by a later version are rejected by LoadState.
+const StateVersion = 1
+
+// State is the Topology of every VPC object in the kernel, as written by
+// "vpc state export".  Objects are named after their unit name.
+type State struct {
+	Version  int       `json:"version" yaml:"version"`
+	Exported time.Time `json:"exported" yaml:"exported"`
+
+	Topology `yaml:",inline"`
+}
+
+// Export returns the State of the VPC objects in snap.  Objects that can't be
+// described by a Topology, e.g. VPC NATs or a port connected to a VPC Router,
+// are left out of the State and reported in the returned list of warnings.
+// Export fails if the kernel can't list the ports of a switch.
+func Export(snap *mgmt.Snapshot) (*State, []string, error) {
+	if !snap.PortsKnown() {
+		return nil, nil, errors.New("unable to export VPC Switch Ports: the kernel can't list the ports of a VPC Switch")
+	}
+
+	s := &State{
+		Version:  StateVersion,
+		Exported: snap.Taken.UTC(),
+		Topology: Topology{
+			Switches: []*Switch{},
+			VMNICs:   []*VMNIC{},
+			EthLinks: []*EthLink{},
+		},
+	}
+
+	var warnings []string
+	warnf := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	peers := make(map[vpc.ID]string)
+	for _, el := range snap.EthLinks {
+		switch {
+		case !el.L2NameKnown:
+			warnf("ethlink %s: the kernel can't report the attached interface, skipping", el.UnitName)
+			continue
+		case el.L2Name == "":
+			warnf("ethlink %s: unable to determine the attached interface, skipping", el.UnitName)
+			continue
+		}
+
+		s.EthLinks = append(s.EthLinks, &EthLink{
+			Name:   el.UnitName,
+			ID:     el.ID.String(),
+			L2Name: el.L2Name,
+		})
+		peers[el.ID] = el.UnitName
+	}
+
+	vmnics := make(map[vpc.ID]bool)
+	for _, nic := range snap.VMNICs {
+		s.VMNICs = append(s.VMNICs, &VMNIC{
+			Name:    nic.UnitName,
+			ID:      nic.ID.String(),
+			MAC:     nic.MAC.String(),
+			MTU:     int(nic.MTU),
+			NQueues: int(nic.NQueues),
+		})
+		peers[nic.ID] = nic.UnitName
+		vmnics[nic.ID] = true
+	}
+
+	for _, sw := range snap.Switches {
+		exported := &Switch{
+			Name:  sw.UnitName,
+			ID:    sw.ID.String(),
+			MAC:   sw.MAC.String(),
+			VNI:   int(sw.VNI),
+			Ports: []*Port{},
+		}
+
+		for _, portID := range sw.Ports {
+			livePort, found := snap.Port(portID)
+			if !found {
+				continue
+			}
+
+			port := &Port{
+				Name:   livePort.UnitName,
+				ID:     livePort.ID.String(),
+				Uplink: livePort.Uplink,
+			}
+
+			if livePort.VNI != sw.VNI {
+				port.VNI = int(livePort.VNI)
+			}
+
+			if livePort.Peer != (vpc.ID{}) {
+				name, found := peers[livePort.Peer]
+				switch {
+				case !found:
+					warnf("switch %s port %s: peer %s is not a vmnic or an ethlink, skipping the connection", sw.UnitName, livePort.UnitName, livePort.Peer)
+				case vmnics[livePort.Peer]:
+					port.VMNIC = name
+				default:
+					port.EthLink = name
+				}
+			}
+
+			exported.Ports = append(exported.Ports, port)
+		}
+
+		s.Switches = append(s.Switches, exported)
+	}
+
+	for _, port := range snap.Ports {
+		if port.Switch == (vpc.ID{}) {
+			warnf("port %s: not attached to a switch, skipping", port.UnitName)
+		}
+	}
+
+	unsupported := []struct {
+		kind string
+		n    int
+	}{
+		{"router", len(snap.Routers)},
+		{"nat", len(snap.NATs)},
+		{"mux", len(snap.Muxes)},
+	}
+	for _, u := range unsupported {
+		if u.n > 0 {
+			warnf("%d %s objects are not supported by state files, skipping", u.n, u.kind)
+		}
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to export VPC state")
+	}
+
+	return s, warnings, nil
+}
+
+// LoadState reads and validates the state file at path.  The format is
+// selected by the file extension: .json, .yaml, or .yml.
+func LoadState(path string) (*State, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read state file %q", path)
+	}
+
+	var s State
+	if err := decode(path, buf, &s); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case s.Version == 0:
+		return nil, errors.Errorf("state file %q has no version", path)
+	case s.Version > StateVersion:
+		return nil, errors.Errorf("state file %q has unsupported version %d (max version: %d)", path, s.Version, StateVersion)
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid state file %q", path)
+	}
+
+	return &s, nil
+}
+
+// Write writes the state file to path.  The format is selected by the file
+// extension: .json, .yaml, or .yml.  The file is replaced atomically so that
+// an interrupted export does not leave a truncated state file behind.
+func (s *State) Write(path string) error {
+	var buf []byte
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		if buf, err = json.MarshalIndent(s, "", "  "); err != nil {
+			return errors.Wrap(err, "unable to encode JSON state")
+		}
+		buf = append(buf, '\n')
+	case ".yaml", ".yml":
+		if buf, err = yaml.Marshal(s); err != nil {
+			return errors.Wrap(err, "unable to encode YAML state")
+		}
+	default:
+		return errors.Errorf("unsupported state file extension %q (valid extensions: .json, .yaml, .yml)", ext)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return errors.Wrapf(err, "unable to create state file %q", path)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "unable to write state file %q", path)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write state file %q", path)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrapf(err, "unable to replace state file %q", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package topology
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// exportState exports the current VPC objects and reloads them from a state
+// file named name.
+func exportState(t *testing.T, name string) *State {
+	t.Helper()
+
+	state, warnings, err := Export(snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to export state: %v", err)
+	}
+
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected export warnings: %v", warnings)
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := state.Write(path); err != nil {
+		t.Fatalf("unable to write state: %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("unable to load state: %v", err)
+	}
+
+	return loaded
+}
+
+// objectIDs returns the IDs of the VPC objects in the kernel.
+func objectIDs(t *testing.T) []string {
+	t.Helper()
+
+	snap := snapshot(t)
+
+	var ids []string
+	for _, sw := range snap.Switches {
+		ids = append(ids, sw.ID.String())
+	}
+	for _, port := range snap.Ports {
+		ids = append(ids, port.ID.String())
+	}
+	for _, nic := range snap.VMNICs {
+		ids = append(ids, nic.ID.String())
+	}
+	for _, el := range snap.EthLinks {
+		ids = append(ids, el.ID.String())
+	}
+
+	return ids
+}
+
+func TestState_RoundTrip(t *testing.T) {
+	for _, name := range []string{"state.json", "state.yaml"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			useSimulator(t)
+			apply(t, testTopology(t))
+			want := objectIDs(t)
+
+			state := exportState(t, name)
+			if state.Version != StateVersion {
+				t.Fatalf("unexpected state version %d", state.Version)
+			}
+
+			// Restore onto an empty host.
+			useSimulator(t)
+			apply(t, &state.Topology)
+
+			if diff := pretty.Compare(objectIDs(t), want); diff != "" {
+				t.Fatalf("restored objects diff: (-got +want)\n%s", diff)
+			}
+
+			p, err := NewPlan(&state.Topology, snapshot(t))
+			if err != nil {
+				t.Fatalf("unable to plan: %v", err)
+			}
+
+			if !p.Empty() {
+				t.Fatalf("restored state differs from the state file: %v", summarize(p))
+			}
+
+			// Exporting the restored host yields the same state.
+			again, _, err := Export(snapshot(t))
+			if err != nil {
+				t.Fatalf("unable to export state: %v", err)
+			}
+
+			if diff := pretty.Compare(again.Topology, state.Topology); diff != "" {
+				t.Fatalf("exported state diff: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadState_Version(t *testing.T) {
+	tests := []struct {
+		version int
+		errStr  string
+	}{
+		{0, "has no version"},
+		{StateVersion + 1, fmt.Sprintf("unsupported version %d", StateVersion+1)},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "state.json")
+		buf := fmt.Sprintf(`{"version": %d, "switches": [], "vmnics": [], "ethlinks": []}`, test.version)
+		if err := ioutil.WriteFile(path, []byte(buf), 0644); err != nil {
+			t.Fatalf("unable to write state file: %v", err)
+		}
+
+		_, err := LoadState(path)
+		if err == nil || !strings.Contains(err.Error(), test.errStr) {
+			t.Errorf("version %d: expected an error containing %q, got %v", test.version, test.errStr, err)
+		}
+	}
+}
+
+func TestState_RestoreSkipExisting(t *testing.T) {
+	useSimulator(t)
+	topo := testTopology(t)
+	apply(t, topo)
+	state := exportState(t, "state.json")
+
+	// Restore onto a host where part of the state already exists and has
+	// drifted from the state file.
+	useSimulator(t)
+	partial := testTopology(t)
+	partial.Switches[0].ID = topo.Switches[0].ID
+	partial.Switches[0].Ports = partial.Switches[0].Ports[:1]
+	partial.Switches[0].Ports[0].ID = topo.Switches[0].Ports[0].ID
+	partial.Switches[0].Ports[0].VNI = 200
+	partial.VMNICs[0].ID = topo.VMNICs[0].ID
+	partial.VMNICs[0].MTU = 1500
+	partial.EthLinks = nil
+	if err := partial.validate(); err != nil {
+		t.Fatalf("invalid partial topology: %v", err)
+	}
+	apply(t, partial)
+
+	p, err := NewPlan(&state.Topology, snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to plan: %v", err)
+	}
+
+	var drift []string
+	for _, act := range p.SkipExisting() {
+		drift = append(drift, act.Kind+" "+act.Detail)
+	}
+
+	wantDrift := []string{"vmnic mtu 1500 -> 9000", "port vni 200 -> 100"}
+	if diff := pretty.Compare(drift, wantDrift); diff != "" {
+		t.Fatalf("drift diff: (-got +want)\n%s", diff)
+	}
+
+	if err := p.Apply(nil); err != nil {
+		t.Fatalf("unable to restore: %v", err)
+	}
+
+	// The missing objects were created, the drifted ones left alone.
+	snap := snapshot(t)
+	uplinkPort, found := snap.Port(topo.Switches[0].Ports[1].id)
+	switch {
+	case !found:
+		t.Fatalf("missing uplink port not restored")
+	case !uplinkPort.Uplink || uplinkPort.Peer != topo.EthLinks[0].id:
+		t.Fatalf("restored uplink port not connected to the ethlink: %+v", uplinkPort)
+	}
+
+	nic, found := snap.VMNIC(topo.VMNICs[0].id)
+	if !found || nic.MTU != 1500 {
+		t.Fatalf("drifted vmnic modified: %+v", nic)
+	}
+
+	port, found := snap.Port(topo.Switches[0].Ports[0].id)
+	if !found || port.VNI != vpc.VNI(200) {
+		t.Fatalf("drifted port modified: %+v", port)
+	}
+}
+
+func TestExport_Unsupported(t *testing.T) {
+	useSimulator(t)
+	apply(t, testTopology(t))
+
+	faults := vpc.NewFaultInjector()
+	defer vpc.SetInterceptors(vpc.SetInterceptors(faults.Interceptor())...)
+
+	// Without the L2 name of the ethlink, it can't be restored.
+	faults.Inject(vpc.Fault{ObjType: vpc.ObjTypeLinkEth, Op: vpc.Op(2), Errno: syscall.EOPNOTSUPP})
+	state, warnings, err := Export(snapshot(t))
+	if err != nil {
+		t.Fatalf("unable to export state: %v", err)
+	}
+
+	if len(state.EthLinks) != 0 || len(warnings) != 2 || !strings.Contains(warnings[0], "can't report the attached interface") {
+		t.Fatalf("unexpected ethlinks %+v, warnings %q", state.EthLinks, warnings)
+	}
+
+	// Without the ports of the switches, nothing can be exported.
+	faults.Inject(vpc.Fault{ObjType: vpc.ObjTypeSwitch, Op: vpc.Op(9), Errno: syscall.EOPNOTSUPP})
+	if _, _, err := Export(snapshot(t)); err == nil || !strings.Contains(err.Error(), "can't list the ports") {
+		t.Fatalf("expected an error, got %v", err)
+	}
+}
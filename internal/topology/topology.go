@@ -0,0 +1,367 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Topology is the set of VPC objects described by a topology file.  Objects
+// reference each other by their name within the file, e.g.:
+//
+//	switch "vpcsw0" {
+//	  id  = "da64c3f3-095d-91e5-df01-5aabcfc52468"
+//	  vni = 123
+//
+//	  port "vm0" {
+//	    id    = "fd436f9c-1f77-11e8-8002-0cc47a6c7d1e"
+//	    vmnic = "vm0"
+//	  }
+//
+//	  port "uplink" {
+//	    id      = "ea58b648-203b-a707-cd02-7a552c8d5295"
+//	    uplink  = true
+//	    ethlink = "em0"
+//	  }
+//	}
+//
+//	vmnic "vm0" {
+//	  id = "07f95a11-6788-2ae7-c306-ba95cff1db38"
+//	}
+//
+//	ethlink "em0" {
+//	  id      = "5c4acd32-1b8d-11e8-b408-0cc47a6c7d1e"
+//	  l2-name = "em0"
+//	}
+//
+// The same topology is expressed in JSON or YAML with the lists "switches",
+// "vmnics", and "ethlinks", each element carrying its "name".
+type Topology struct {
+	Switches []*Switch  `hcl:"switch" json:"switches" yaml:"switches"`
+	VMNICs   []*VMNIC   `hcl:"vmnic" json:"vmnics" yaml:"vmnics"`
+	EthLinks []*EthLink `hcl:"ethlink" json:"ethlinks" yaml:"ethlinks"`
+}
+
+// Switch is a VPC Switch and its ports.
+type Switch struct {
+	Name  string  `hcl:",key" json:"name" yaml:"name"`
+	ID    string  `hcl:"id" json:"id" yaml:"id"`
+	MAC   string  `hcl:"mac" json:"mac,omitempty" yaml:"mac,omitempty"`
+	VNI   int     `hcl:"vni" json:"vni" yaml:"vni"`
+	Ports []*Port `hcl:"port" json:"ports" yaml:"ports"`
+
+	id  vpc.ID
+	mac net.HardwareAddr
+}
+
+// Port is a VPC Switch Port.  A port is connected to at most one VM NIC or
+// EthLink.  The VNI defaults to the VNI of the switch.
+type Port struct {
+	Name    string `hcl:",key" json:"name" yaml:"name"`
+	ID      string `hcl:"id" json:"id" yaml:"id"`
+	VNI     int    `hcl:"vni" json:"vni,omitempty" yaml:"vni,omitempty"`
+	Uplink  bool   `hcl:"uplink" json:"uplink,omitempty" yaml:"uplink,omitempty"`
+	VMNIC   string `hcl:"vmnic" json:"vmnic,omitempty" yaml:"vmnic,omitempty"`
+	EthLink string `hcl:"ethlink" json:"ethlink,omitempty" yaml:"ethlink,omitempty"`
+
+	id   vpc.ID
+	vni  vpc.VNI
+	peer vpc.ID
+}
+
+// VMNIC is a VM NIC.  The MAC address defaults to the node of the VM NIC ID.
+// The MTU and number of queues are left to the kernel default when unset.
+type VMNIC struct {
+	Name    string `hcl:",key" json:"name" yaml:"name"`
+	ID      string `hcl:"id" json:"id" yaml:"id"`
+	MAC     string `hcl:"mac" json:"mac,omitempty" yaml:"mac,omitempty"`
+	MTU     int    `hcl:"mtu" json:"mtu,omitempty" yaml:"mtu,omitempty"`
+	NQueues int    `hcl:"num-queues" json:"num-queues,omitempty" yaml:"num-queues,omitempty"`
+
+	id  vpc.ID
+	mac net.HardwareAddr
+}
+
+// EthLink is a VPC EthLink attached to the physical interface L2Name.
+type EthLink struct {
+	Name   string `hcl:",key" json:"name" yaml:"name"`
+	ID     string `hcl:"id" json:"id" yaml:"id"`
+	L2Name string `hcl:"l2-name" json:"l2-name" yaml:"l2-name"`
+
+	id vpc.ID
+}
+
+// _HCLKeys is the set of keys permitted in an HCL object.  The value is the
+// schema of a nested block, or nil for an attribute.
+type _HCLKeys map[string]_HCLKeys
+
+// _HCLSchema catches misspelled keys, which the HCL decoder silently ignores.
+var _HCLSchema = _HCLKeys{
+	"switch": {
+		"id":  nil,
+		"mac": nil,
+		"vni": nil,
+		"port": {
+			"id":      nil,
+			"vni":     nil,
+			"uplink":  nil,
+			"vmnic":   nil,
+			"ethlink": nil,
+		},
+	},
+	"vmnic": {
+		"id":         nil,
+		"mac":        nil,
+		"mtu":        nil,
+		"num-queues": nil,
+	},
+	"ethlink": {
+		"id":      nil,
+		"l2-name": nil,
+	},
+}
+
+func (keys _HCLKeys) check(node ast.Node) error {
+	list, ok := node.(*ast.ObjectList)
+	if !ok {
+		return nil
+	}
+
+	for _, item := range list.Items {
+		key := item.Keys[0].Token.Value().(string)
+
+		nested, found := keys[key]
+		if !found {
+			return errors.Errorf("%s: unknown key %q", item.Pos(), key)
+		}
+
+		if obj, ok := item.Val.(*ast.ObjectType); ok && nested != nil {
+			if err := nested.check(obj.List); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Load reads and validates the topology file at path.  The format is selected
+// by the file extension: .hcl, .json, .yaml, or .yml.
+func Load(path string) (*Topology, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read topology file %q", path)
+	}
+
+	var t Topology
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".hcl":
+		root, err := hcl.ParseBytes(buf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse HCL topology file %q", path)
+		}
+
+		if err := _HCLSchema.check(root.Node); err != nil {
+			return nil, errors.Wrapf(err, "invalid HCL topology file %q", path)
+		}
+
+		if err := hcl.DecodeObject(&t, root); err != nil {
+			return nil, errors.Wrapf(err, "unable to decode HCL topology file %q", path)
+		}
+	case ".json", ".yaml", ".yml":
+		if err := decode(path, buf, &t); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported topology file extension %q (valid extensions: .hcl, .json, .yaml, .yml)", ext)
+	}
+
+	if err := t.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid topology file %q", path)
+	}
+
+	return &t, nil
+}
+
+// decode strictly decodes the JSON or YAML file at path into v.
+func decode(path string, buf []byte, v interface{}) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(buf))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(v); err != nil {
+			return errors.Wrapf(err, "unable to decode JSON file %q", path)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.UnmarshalStrict(buf, v); err != nil {
+			return errors.Wrapf(err, "unable to decode YAML file %q", path)
+		}
+	default:
+		return errors.Errorf("unsupported file extension %q (valid extensions: .json, .yaml, .yml)", ext)
+	}
+
+	return nil
+}
+
+// validate parses the IDs and MAC addresses of every object and resolves the
+// references between objects.
+func (t *Topology) validate() error {
+	ids := make(map[vpc.ID]string)
+	addID := func(kind, name, idStr string, objType vpc.ObjType) (vpc.ID, error) {
+		if name == "" {
+			return vpc.ID{}, errors.Errorf("%s without a name", kind)
+		}
+
+		id, err := vpc.ParseID(idStr)
+		if err != nil {
+			return vpc.ID{}, errors.Wrapf(err, "%s %q: unable to parse ID %q", kind, name, idStr)
+		}
+
+		if id.ObjType != objType {
+			return vpc.ID{}, errors.Errorf("%s %q: ID %s is a %s ID, not a %s ID", kind, name, id, id.ObjType, objType)
+		}
+
+		if other, found := ids[id]; found {
+			return vpc.ID{}, errors.Errorf("%s %q: ID %s is already used by %s", kind, name, id, other)
+		}
+		ids[id] = kind + " " + name
+
+		return id, nil
+	}
+
+	parseMAC := func(kind, name, macStr string, id vpc.ID) (net.HardwareAddr, error) {
+		if macStr == "" {
+			return id.Node[:], nil
+		}
+
+		mac, err := net.ParseMAC(macStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %q: unable to parse MAC %q", kind, name, macStr)
+		}
+
+		return mac, nil
+	}
+
+	vmnics := make(map[string]*VMNIC, len(t.VMNICs))
+	for _, nic := range t.VMNICs {
+		var err error
+		if nic.id, err = addID("vmnic", nic.Name, nic.ID, vpc.ObjTypeNICVM); err != nil {
+			return err
+		}
+
+		if nic.mac, err = parseMAC("vmnic", nic.Name, nic.MAC, nic.id); err != nil {
+			return err
+		}
+
+		switch {
+		case nic.MTU < 0 || int64(nic.MTU) > math.MaxUint32:
+			return errors.Errorf("vmnic %q: invalid mtu %d", nic.Name, nic.MTU)
+		case nic.NQueues < 0 || nic.NQueues > 0xffff:
+			return errors.Errorf("vmnic %q: invalid num-queues %d", nic.Name, nic.NQueues)
+		case vmnics[nic.Name] != nil:
+			return errors.Errorf("vmnic %q: duplicate name", nic.Name)
+		}
+		vmnics[nic.Name] = nic
+	}
+
+	ethLinks := make(map[string]*EthLink, len(t.EthLinks))
+	for _, el := range t.EthLinks {
+		var err error
+		if el.id, err = addID("ethlink", el.Name, el.ID, vpc.ObjTypeLinkEth); err != nil {
+			return err
+		}
+
+		switch {
+		case el.L2Name == "":
+			return errors.Errorf("ethlink %q: missing l2-name", el.Name)
+		case ethLinks[el.Name] != nil:
+			return errors.Errorf("ethlink %q: duplicate name", el.Name)
+		}
+		ethLinks[el.Name] = el
+	}
+
+	switches := make(map[string]*Switch, len(t.Switches))
+	peers := make(map[vpc.ID]string)
+	for _, sw := range t.Switches {
+		var err error
+		if sw.id, err = addID("switch", sw.Name, sw.ID, vpc.ObjTypeSwitch); err != nil {
+			return err
+		}
+
+		if sw.mac, err = parseMAC("switch", sw.Name, sw.MAC, sw.id); err != nil {
+			return err
+		}
+
+		switch {
+		case sw.VNI < int(vpc.VNIMin) || sw.VNI > int(vpc.VNIMax):
+			return errors.Errorf("switch %q: invalid VNI %d", sw.Name, sw.VNI)
+		case switches[sw.Name] != nil:
+			return errors.Errorf("switch %q: duplicate name", sw.Name)
+		}
+		switches[sw.Name] = sw
+
+		var uplinks int
+		ports := make(map[string]*Port, len(sw.Ports))
+		for _, port := range sw.Ports {
+			kind := "switch " + sw.Name + " port"
+			if port.id, err = addID(kind, port.Name, port.ID, vpc.ObjTypeSwitchPort); err != nil {
+				return err
+			}
+
+			port.vni = vpc.VNI(sw.VNI)
+			if port.VNI != 0 {
+				port.vni = vpc.VNI(port.VNI)
+			}
+
+			switch {
+			case port.VNI < int(vpc.VNIMin) || port.VNI > int(vpc.VNIMax):
+				return errors.Errorf("%s %q: invalid VNI %d", kind, port.Name, port.VNI)
+			case ports[port.Name] != nil:
+				return errors.Errorf("%s %q: duplicate name", kind, port.Name)
+			case port.VMNIC != "" && port.EthLink != "":
+				return errors.Errorf("%s %q: connected to both a vmnic and an ethlink", kind, port.Name)
+			case port.VMNIC != "":
+				nic, found := vmnics[port.VMNIC]
+				if !found {
+					return errors.Errorf("%s %q: unknown vmnic %q", kind, port.Name, port.VMNIC)
+				}
+				port.peer = nic.id
+			case port.EthLink != "":
+				el, found := ethLinks[port.EthLink]
+				if !found {
+					return errors.Errorf("%s %q: unknown ethlink %q", kind, port.Name, port.EthLink)
+				}
+				port.peer = el.id
+			}
+
+			ports[port.Name] = port
+
+			if port.Uplink {
+				uplinks++
+			}
+
+			if port.peer != (vpc.ID{}) {
+				if other, found := peers[port.peer]; found {
+					return errors.Errorf("%s %q: %s is already connected to port %s", kind, port.Name, port.peer, other)
+				}
+				peers[port.peer] = port.Name
+			}
+		}
+
+		if uplinks > 1 {
+			return errors.Errorf("switch %q: more than one uplink port", sw.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package topology
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/freebsd/freebsd/libexec/go/src/go.freebsd.org/sys/vpc"
+)
+
+// testTopology returns a validated Topology with a switch connecting a vmnic
+// and an uplink ethlink.
+func testTopology(t *testing.T) *Topology {
+	t.Helper()
+
+	topo := &Topology{
+		VMNICs: []*VMNIC{
+			{Name: "vm0", ID: vpc.GenID(vpc.ObjTypeNICVM).String(), MTU: 9000},
+		},
+		EthLinks: []*EthLink{
+			{Name: "uplink", ID: vpc.GenID(vpc.ObjTypeLinkEth).String(), L2Name: "em0"},
+		},
+		Switches: []*Switch{
+			{
+				Name: "sw0",
+				ID:   vpc.GenID(vpc.ObjTypeSwitch).String(),
+				VNI:  100,
+				Ports: []*Port{
+					{Name: "p-vm0", ID: vpc.GenID(vpc.ObjTypeSwitchPort).String(), VMNIC: "vm0"},
+					{Name: "p-uplink", ID: vpc.GenID(vpc.ObjTypeSwitchPort).String(), EthLink: "uplink", Uplink: true},
+				},
+			},
+		},
+	}
+
+	if err := topo.validate(); err != nil {
+		t.Fatalf("invalid test topology: %v", err)
+	}
+
+	return topo
+}
+
+func TestTopology_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(topo *Topology)
+		errStr string
+	}{
+		{
+			name: "duplicate switch name",
+			modify: func(topo *Topology) {
+				topo.Switches = append(topo.Switches, &Switch{
+					Name: "sw0",
+					ID:   vpc.GenID(vpc.ObjTypeSwitch).String(),
+					VNI:  200,
+				})
+			},
+			errStr: `switch "sw0": duplicate name`,
+		},
+		{
+			name: "duplicate port name",
+			modify: func(topo *Topology) {
+				sw := topo.Switches[0]
+				sw.Ports = append(sw.Ports, &Port{
+					Name: "p-vm0",
+					ID:   vpc.GenID(vpc.ObjTypeSwitchPort).String(),
+				})
+			},
+			errStr: `switch sw0 port "p-vm0": duplicate name`,
+		},
+		{
+			name: "duplicate vmnic name",
+			modify: func(topo *Topology) {
+				topo.VMNICs = append(topo.VMNICs, &VMNIC{
+					Name: "vm0",
+					ID:   vpc.GenID(vpc.ObjTypeNICVM).String(),
+				})
+			},
+			errStr: `vmnic "vm0": duplicate name`,
+		},
+		{
+			name: "wrong ID type",
+			modify: func(topo *Topology) {
+				topo.Switches[0].Ports[0].ID = vpc.GenID(vpc.ObjTypeSwitch).String()
+			},
+			errStr: "is a vpcsw ID, not a vpcp ID",
+		},
+		{
+			name: "duplicate ID",
+			modify: func(topo *Topology) {
+				topo.Switches[0].Ports[1].ID = topo.Switches[0].Ports[0].ID
+			},
+			errStr: "is already used by switch sw0 port p-vm0",
+		},
+		{
+			name: "unknown vmnic",
+			modify: func(topo *Topology) {
+				topo.Switches[0].Ports[0].VMNIC = "vm1"
+			},
+			errStr: `unknown vmnic "vm1"`,
+		},
+		{
+			name: "shared peer",
+			modify: func(topo *Topology) {
+				topo.Switches[0].Ports[1].EthLink = ""
+				topo.Switches[0].Ports[1].VMNIC = "vm0"
+			},
+			errStr: "is already connected to port p-vm0",
+		},
+		{
+			name: "two uplinks",
+			modify: func(topo *Topology) {
+				topo.Switches[0].Ports[0].Uplink = true
+			},
+			errStr: `switch "sw0": more than one uplink port`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			topo := testTopology(t)
+			test.modify(topo)
+
+			err := topo.validate()
+			if err == nil || !strings.Contains(err.Error(), test.errStr) {
+				t.Fatalf("expected an error containing %q, got %v", test.errStr, err)
+			}
+		})
+	}
+
+	// Port names only need to be unique within a switch.
+	topo := testTopology(t)
+	topo.Switches = append(topo.Switches, &Switch{
+		Name:  "sw1",
+		ID:    vpc.GenID(vpc.ObjTypeSwitch).String(),
+		VNI:   200,
+		Ports: []*Port{{Name: "p-vm0", ID: vpc.GenID(vpc.ObjTypeSwitchPort).String()}},
+	})
+	if err := topo.validate(); err != nil {
+		t.Fatalf("port names shared across switches rejected: %v", err)
+	}
+}